@@ -0,0 +1,77 @@
+// Command genkey creates a JWT signing key in JWT_KEY_DIR.
+//
+//	go run ./cmd/genkey                 # Ed25519 key named after today's date
+//	go run ./cmd/genkey -type rsa       # RSA-2048 key, for partners without EdDSA
+//	go run ./cmd/genkey -kid 2025-q3    # pick the kid yourself
+//
+// Unless JWT_SIGNING_KID says otherwise, the key whose name sorts last signs
+// new tokens; the others keep verifying the tokens they signed until they're
+// removed.
+package main
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	godotenv.Load()
+
+	defaultDir := os.Getenv("JWT_KEY_DIR")
+	if defaultDir == "" {
+		defaultDir = "./keys"
+	}
+
+	dir := flag.String("dir", defaultDir, "key directory")
+	keyType := flag.String("type", "ed25519", "key type: ed25519 or rsa")
+	kid := flag.String("kid", time.Now().UTC().Format("2006-01-02"), "key ID, used as the file name")
+	flag.Parse()
+
+	var key crypto.Signer
+	var err error
+	switch *keyType {
+	case "ed25519":
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	case "rsa":
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+	default:
+		log.Fatalf("unknown key type %q (use ed25519 or rsa)", *keyType)
+	}
+	if err != nil {
+		log.Fatal("Failed to generate key: ", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		log.Fatal("Failed to encode key: ", err)
+	}
+
+	if err := os.MkdirAll(*dir, 0o700); err != nil {
+		log.Fatal("Failed to create key directory: ", err)
+	}
+	path := filepath.Join(*dir, *kid+".pem")
+
+	// Never overwrite a key: tokens it signed would stop verifying
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		log.Fatal("Failed to create key file: ", err)
+	}
+	defer file.Close()
+	if err := pem.Encode(file, &pem.Block{Type: "PRIVATE KEY", Bytes: der}); err != nil {
+		log.Fatal("Failed to write key: ", err)
+	}
+
+	fmt.Printf("Wrote %s key %q to %s\n", *keyType, *kid, path)
+}
@@ -15,7 +15,9 @@ var DB *gorm.DB
 func InitDB() {
 	var err error
 	dsn := os.Getenv("DATABASE_URL")
-	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	// TranslateError turns unique violations into gorm.ErrDuplicatedKey, so
+	// handlers can tell a lost race from a broken database
+	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
@@ -39,16 +41,116 @@ func autoMigrate() {
 		&Company{},
 		&Project{},
 		&ProjectImage{},
+		&UploadedImage{},
 		&Like{},
 		&Comment{},
 		&Follow{},
 		&Category{},
+		&Session{},
+		&PasswordResetToken{},
+		&LoginAttempt{},
+		&RecoveryCode{},
+		&UserIdentity{},
+		&OIDCLoginState{},
+		&APIKey{},
+		&RolePermission{},
+		&ProjectDailyView{},
+		&AccountStatusChange{},
+		&AuditEvent{},
+		&HandleRedirect{},
+		&CompanyInvitation{},
+		&Shortlist{},
+		&ShortlistEntry{},
+		&ShortlistNote{},
+		&HireInquiry{},
+		&HireInquiryEvent{},
+		&Gig{},
+		&GigApplication{},
+		&GigApplicationProject{},
+		&AvailabilityWindow{},
+		&RateCard{},
+		&ProjectRevision{},
 	)
 	if err != nil {
 		log.Printf("Auto-migration error: %v", err)
 	} else {
 		fmt.Println("✅ All tables migrated successfully")
 	}
+
+	protectAuditLog()
+	indexHandles()
+	backfillPublishAt()
+	backfillCompanyOwners()
+	backfillNoPassword()
+}
+
+// backfillPublishAt dates projects from before publishing could be scheduled;
+// they went live when they were created
+func backfillPublishAt() {
+	if err := DB.Exec(`UPDATE projects SET publish_at = created_at WHERE status = 'published' AND publish_at IS NULL`).Error; err != nil {
+		log.Printf("Failed to backfill project publish dates: %v", err)
+	}
+}
+
+// backfillCompanyOwners gives companies from before ownership was recorded
+// their creator as owner: the earliest member who didn't join by invitation
+func backfillCompanyOwners() {
+	if err := DB.Exec(`UPDATE companies SET owner_id = (
+			SELECT users.id FROM users WHERE users.company_id = companies.id
+			ORDER BY EXISTS (SELECT 1 FROM company_invitations WHERE company_invitations.accepted_by_id = users.id AND company_invitations.company_id = companies.id),
+				users.created_at
+			LIMIT 1
+		) WHERE owner_id IS NULL`).Error; err != nil {
+		log.Printf("Failed to backfill company owners: %v", err)
+	}
+}
+
+// backfillNoPassword flags social sign-ups from before NoPassword existed:
+// accounts created together with their first identity that never reset
+// their password
+func backfillNoPassword() {
+	if err := DB.Exec(`UPDATE users SET no_password = true WHERE no_password = false
+		AND EXISTS (SELECT 1 FROM user_identities WHERE user_identities.user_id = users.id
+			AND user_identities.created_at < users.created_at + interval '5 seconds')
+		AND NOT EXISTS (SELECT 1 FROM password_reset_tokens WHERE password_reset_tokens.user_id = users.id
+			AND password_reset_tokens.used_at IS NOT NULL)`).Error; err != nil {
+		log.Printf("Failed to backfill social sign-ups without a password: %v", err)
+	}
+}
+
+// indexHandles enforces case-insensitive uniqueness of usernames, which GORM
+// tags can't express
+func indexHandles() {
+	if err := DB.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username_lower ON users (LOWER(username))`).Error; err != nil {
+		log.Printf("Failed to index usernames: %v", err)
+	}
+}
+
+// protectAuditLog makes audit_events append-only at the database level, so
+// not even a bug or a stray admin query can rewrite history
+func protectAuditLog() {
+	statements := []string{
+		`CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'audit_events is append-only';
+		END;
+		$$ LANGUAGE plpgsql`,
+		`DO $$
+		BEGIN
+			IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'audit_events_no_modify') THEN
+				CREATE TRIGGER audit_events_no_modify
+					BEFORE UPDATE OR DELETE ON audit_events
+					FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+			END IF;
+		END
+		$$`,
+	}
+	for _, stmt := range statements {
+		if err := DB.Exec(stmt).Error; err != nil {
+			log.Printf("Failed to protect audit log: %v", err)
+			return
+		}
+	}
 }
 
 // User model - for creatives, companies, and admins
@@ -57,6 +159,7 @@ type User struct {
 	Name        string    `gorm:"type:varchar(255);not null"`
 	Email       string    `gorm:"type:varchar(255);unique;not null"`
 	Password    string    `gorm:"type:varchar(255);not null"`
+	NoPassword  bool      `gorm:"default:false"`                       // Social sign-up whose password is random and unknown, until reset
 	Role        string    `gorm:"type:varchar(50);default:'creative'"` // creative, company, admin
 	Bio         string    `gorm:"type:text"`
 	Location    string    `gorm:"type:varchar(255)"`
@@ -73,6 +176,25 @@ type User struct {
 	Company     *Company  `gorm:"foreignKey:CompanyID"`
 	CreatedAt   time.Time `gorm:"autoCreateTime"`
 	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+
+	// Two-factor authentication
+	TOTPSecret        string `gorm:"type:varchar(64)"` // Set during enrollment, active once TOTPEnabled
+	TOTPEnabled       bool   `gorm:"default:false"`
+	TOTPLastStep      int64  `gorm:"default:0"`     // Last accepted time step, prevents code replay
+	TwoFactorRequired bool   `gorm:"default:false"` // Forced enrollment (set by admins)
+
+	// Moderation
+	Status          string     `gorm:"type:varchar(20);default:'active';index"` // active, suspended, banned
+	SuspendedUntil  *time.Time // Only for suspended accounts
+	StatusReason    string     `gorm:"type:text"`
+	StatusChangedBy *uint      // Admin who last changed the status
+	StatusChangedAt *time.Time
+
+	// Account deletion
+	DeletionScheduledAt *time.Time `gorm:"index"` // Set while a deletion request is in its grace period
+
+	// Vanity profile URL, unique regardless of case (see indexHandles)
+	Username *string `gorm:"type:varchar(30)"`
 }
 
 // Company model - for recruiters/organizations
@@ -85,6 +207,7 @@ type Company struct {
 	LogoURL     string    `gorm:"type:text"`
 	Industry    string    `gorm:"type:varchar(100)"`
 	Size        string    `gorm:"type:varchar(50)"`
+	OwnerID     *uint     `gorm:"index"` // Member who manages the team; nil once nobody is left
 	CreatedAt   time.Time `gorm:"autoCreateTime"`
 	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
 }
@@ -103,7 +226,9 @@ type Project struct {
 	Images      []ProjectImage `gorm:"foreignKey:ProjectID"`
 	Views       int            `gorm:"default:0"`
 	LikesCount  int            `gorm:"default:0"`
-	Featured    bool           `gorm:"default:false"` // For admin to feature projects
+	Featured    bool           `gorm:"default:false"`                                       // For admin to feature projects
+	Status      string         `gorm:"type:varchar(20);not null;default:'published';index"` // draft, scheduled, published, archived
+	PublishAt   *time.Time     `gorm:"index"`                                               // When a scheduled project goes live, or when a published one did
 	CreatedAt   time.Time      `gorm:"autoCreateTime"`
 	UpdatedAt   time.Time      `gorm:"autoUpdateTime"`
 	DeletedAt   *time.Time     `gorm:"index"`
@@ -118,6 +243,16 @@ type ProjectImage struct {
 	CreatedAt time.Time `gorm:"autoCreateTime"`
 }
 
+// UploadedImage model - who uploaded each image to our Cloudinary account,
+// so users can only attach, and account deletion only destroys, their own
+type UploadedImage struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	PublicID  string    `gorm:"type:varchar(255);not null;index"`
+	ImageURL  string    `gorm:"type:text;not null;index"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
 // Category model - creative categories
 type Category struct {
 	ID        uint      `gorm:"primaryKey"`
@@ -140,14 +275,15 @@ type Like struct {
 
 // Comment model - users comment on projects
 type Comment struct {
-	ID        uint      `gorm:"primaryKey"`
-	UserID    uint      `gorm:"not null;index"`
-	User      User      `gorm:"foreignKey:UserID"`
-	ProjectID uint      `gorm:"not null;index"`
-	Project   Project   `gorm:"foreignKey:ProjectID"`
-	Content   string    `gorm:"type:text;not null"`
-	CreatedAt time.Time `gorm:"autoCreateTime"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	ID        uint       `gorm:"primaryKey"`
+	UserID    uint       `gorm:"not null;index"`
+	User      User       `gorm:"foreignKey:UserID"`
+	ProjectID uint       `gorm:"not null;index"`
+	Project   Project    `gorm:"foreignKey:ProjectID"`
+	Content   string     `gorm:"type:text;not null"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt time.Time  `gorm:"autoUpdateTime"`
+	DeletedAt *time.Time `gorm:"index"` // Soft-deleted by moderators
 }
 
 // Follow model - users follow other users
@@ -159,3 +295,308 @@ type Follow struct {
 	Following   User      `gorm:"foreignKey:FollowingID"`
 	CreatedAt   time.Time `gorm:"autoCreateTime"`
 }
+
+// Session model - one row per logged-in device, backing refresh tokens
+type Session struct {
+	ID                uint      `gorm:"primaryKey"`
+	UserID            uint      `gorm:"not null;index"`
+	User              User      `gorm:"foreignKey:UserID"`
+	RefreshTokenHash  string    `gorm:"type:varchar(64);uniqueIndex;not null"` // SHA-256 of current refresh token
+	PreviousTokenHash string    `gorm:"type:varchar(64);index"`                // Rotated-out token, used to detect reuse
+	UserAgent         string    `gorm:"type:text"`
+	IPAddress         string    `gorm:"type:varchar(64)"`
+	ExpiresAt         time.Time `gorm:"not null"`
+	LastUsedAt        time.Time
+	RevokedAt         *time.Time `gorm:"index"`
+	ImpersonatorID    *uint      `gorm:"index"` // Admin behind an impersonation session
+	CreatedAt         time.Time  `gorm:"autoCreateTime"`
+}
+
+// PasswordResetToken model - single-use password reset links
+type PasswordResetToken struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	TokenHash string    `gorm:"type:varchar(64);uniqueIndex;not null"` // SHA-256 of the emailed token
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// LoginAttempt model - failed login counters per account or IP
+type LoginAttempt struct {
+	Key           string    `gorm:"type:varchar(320);primaryKey"` // "account:<email>" or "ip:<address>", prefixed by the kind for mail limits
+	Failures      int       `gorm:"not null;default:0"`
+	LastFailureAt time.Time `gorm:"not null"`
+	LockedUntil   time.Time
+	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+}
+
+// RecoveryCode model - hashed one-time 2FA recovery codes
+type RecoveryCode struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;index"`
+	CodeHash  string `gorm:"type:varchar(64);not null;index"`
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// UserIdentity model - external (OIDC) accounts linked to a user
+type UserIdentity struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	User      User      `gorm:"foreignKey:UserID"`
+	Provider  string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_identity_provider_subject"` // e.g. google, linkedin
+	Subject   string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_identity_provider_subject"`
+	Email     string    `gorm:"type:varchar(255)"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// OIDCLoginState model - pending social logins between redirect and callback
+type OIDCLoginState struct {
+	ID           uint      `gorm:"primaryKey"`
+	StateHash    string    `gorm:"type:varchar(64);uniqueIndex;not null"`
+	Provider     string    `gorm:"type:varchar(50);not null"`
+	CodeVerifier string    `gorm:"type:varchar(128);not null"` // PKCE verifier, never leaves the server
+	Nonce        string    `gorm:"type:varchar(128);not null"`
+	LinkUserID   *uint     // Set when a logged-in user is linking an account
+	ExpiresAt    time.Time `gorm:"not null"`
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+}
+
+// APIKey model - personal API keys for scripts and integrations
+type APIKey struct {
+	ID         uint   `gorm:"primaryKey"`
+	UserID     uint   `gorm:"not null;index"`
+	User       User   `gorm:"foreignKey:UserID"`
+	Name       string `gorm:"type:varchar(100);not null"`
+	Prefix     string `gorm:"type:varchar(20);not null"`             // First characters, for display only
+	KeyHash    string `gorm:"type:varchar(64);uniqueIndex;not null"` // SHA-256 of the full key
+	Scopes     string `gorm:"type:text;not null"`                    // Comma-separated
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time `gorm:"index"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime"`
+}
+
+// RolePermission model - permissions granted to each role
+type RolePermission struct {
+	ID         uint      `gorm:"primaryKey"`
+	Role       string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_role_permission"`
+	Permission string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_role_permission"` // e.g. project.feature
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
+
+// ProjectDailyView model - per-day view counts for analytics
+type ProjectDailyView struct {
+	ProjectID uint      `gorm:"primaryKey"`
+	Day       time.Time `gorm:"type:date;primaryKey"`
+	Views     int       `gorm:"not null;default:0"`
+}
+
+// AccountStatusChange model - history of suspensions, bans and reinstatements
+type AccountStatusChange struct {
+	ID             uint   `gorm:"primaryKey"`
+	UserID         uint   `gorm:"not null;index"`
+	Status         string `gorm:"type:varchar(20);not null"`
+	Reason         string `gorm:"type:text"`
+	SuspendedUntil *time.Time
+	ActorID        uint      `gorm:"not null"` // Admin who took the action
+	Actor          User      `gorm:"foreignKey:ActorID"`
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+}
+
+// AuditEvent model - append-only log of security-relevant and admin actions
+type AuditEvent struct {
+	ID         uint      `gorm:"primaryKey"`
+	ActorID    *uint     `gorm:"index"`                            // Nil when the actor is unknown (e.g. failed login)
+	Action     string    `gorm:"type:varchar(100);not null;index"` // e.g. user.role_change, project.delete
+	TargetType string    `gorm:"type:varchar(50);index:idx_audit_target"`
+	TargetID   string    `gorm:"type:varchar(100);index:idx_audit_target"`
+	IPAddress  string    `gorm:"type:varchar(64)"`
+	UserAgent  string    `gorm:"type:text"`
+	Changes    string    `gorm:"type:jsonb;not null;default:'{}'"` // JSON diff: {"field": {"from": ..., "to": ...}}
+	CreatedAt  time.Time `gorm:"autoCreateTime;index"`
+}
+
+// HandleRedirect model - keeps a renamed handle resolving to its user
+type HandleRedirect struct {
+	ID        uint      `gorm:"primaryKey"`
+	Handle    string    `gorm:"type:varchar(30);uniqueIndex;not null"` // Lowercased old handle
+	UserID    uint      `gorm:"not null;index"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// CompanyInvitation model - invites another recruiter to join a company
+type CompanyInvitation struct {
+	ID           uint      `gorm:"primaryKey"`
+	CompanyID    uint      `gorm:"not null;index"`
+	Company      Company   `gorm:"foreignKey:CompanyID"`
+	Email        string    `gorm:"type:varchar(255);not null;index"`
+	TokenHash    string    `gorm:"type:varchar(64);uniqueIndex;not null"` // SHA-256 of the emailed token
+	InvitedByID  uint      `gorm:"not null"`
+	InvitedBy    User      `gorm:"foreignKey:InvitedByID"`
+	ExpiresAt    time.Time `gorm:"not null"`
+	AcceptedAt   *time.Time
+	AcceptedByID *uint
+	RevokedAt    *time.Time
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+}
+
+// Shortlist model - a company's private list of candidates
+type Shortlist struct {
+	ID          uint             `gorm:"primaryKey"`
+	CompanyID   uint             `gorm:"not null;index"`
+	Name        string           `gorm:"type:varchar(255);not null"`
+	Description string           `gorm:"type:text"`
+	CreatedByID uint             `gorm:"not null"`
+	CreatedBy   User             `gorm:"foreignKey:CreatedByID"`
+	Entries     []ShortlistEntry `gorm:"foreignKey:ShortlistID"`
+	CreatedAt   time.Time        `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time        `gorm:"autoUpdateTime"`
+}
+
+// ShortlistEntry model - a creative on a shortlist and where they are in the
+// hiring process
+type ShortlistEntry struct {
+	ID          uint            `gorm:"primaryKey"`
+	ShortlistID uint            `gorm:"not null;uniqueIndex:idx_shortlist_candidate"`
+	CandidateID uint            `gorm:"not null;uniqueIndex:idx_shortlist_candidate;index"`
+	Candidate   User            `gorm:"foreignKey:CandidateID"`
+	Status      string          `gorm:"type:varchar(20);not null;default:'new'"` // new, contacted, interviewing, hired
+	AddedByID   uint            `gorm:"not null"`
+	AddedBy     User            `gorm:"foreignKey:AddedByID"`
+	Notes       []ShortlistNote `gorm:"foreignKey:EntryID"`
+	CreatedAt   time.Time       `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time       `gorm:"autoUpdateTime"`
+}
+
+// ShortlistNote model - private recruiter note about a shortlisted candidate
+type ShortlistNote struct {
+	ID        uint      `gorm:"primaryKey"`
+	EntryID   uint      `gorm:"not null;index"`
+	AuthorID  uint      `gorm:"not null"`
+	Author    User      `gorm:"foreignKey:AuthorID"`
+	Content   string    `gorm:"type:text;not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// HireInquiry model - a company asking a for-hire creative to take on work.
+// Budget and timeline hold the current terms, updated by counter-offers.
+type HireInquiry struct {
+	ID         uint               `gorm:"primaryKey"`
+	CompanyID  uint               `gorm:"not null;index"`
+	Company    Company            `gorm:"foreignKey:CompanyID"`
+	SenderID   uint               `gorm:"not null"`
+	Sender     User               `gorm:"foreignKey:SenderID"`
+	CreativeID uint               `gorm:"not null;index"`
+	Creative   User               `gorm:"foreignKey:CreativeID"`
+	Title      string             `gorm:"type:varchar(255);not null"`
+	Brief      string             `gorm:"type:text;not null"`
+	BudgetMin  int                `gorm:"not null"`
+	BudgetMax  int                `gorm:"not null"`
+	Currency   string             `gorm:"type:varchar(3);not null"`
+	Timeline   string             `gorm:"type:varchar(255);not null"`                        // e.g. "6 weeks from March"
+	Status     string             `gorm:"type:varchar(20);not null;default:'pending';index"` // pending, countered, accepted, declined, withdrawn
+	Events     []HireInquiryEvent `gorm:"foreignKey:InquiryID"`
+	CreatedAt  time.Time          `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time          `gorm:"autoUpdateTime"`
+}
+
+// HireInquiryEvent model - status history of an inquiry, including the terms
+// proposed with each counter-offer
+type HireInquiryEvent struct {
+	ID        uint   `gorm:"primaryKey"`
+	InquiryID uint   `gorm:"not null;index"`
+	ActorID   uint   `gorm:"not null"`
+	Actor     User   `gorm:"foreignKey:ActorID"`
+	Status    string `gorm:"type:varchar(20);not null"`
+	Message   string `gorm:"type:text"`
+	BudgetMin *int   // Only set when the terms changed
+	BudgetMax *int
+	Timeline  string    `gorm:"type:varchar(255)"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// Gig model - a paid creative brief posted by a company
+type Gig struct {
+	ID          uint      `gorm:"primaryKey"`
+	CompanyID   uint      `gorm:"not null;index"`
+	Company     Company   `gorm:"foreignKey:CompanyID"`
+	PostedByID  uint      `gorm:"not null"`
+	PostedBy    User      `gorm:"foreignKey:PostedByID"`
+	CategoryID  uint      `gorm:"not null;index"`
+	Category    Category  `gorm:"foreignKey:CategoryID"`
+	Title       string    `gorm:"type:varchar(255);not null"`
+	Description string    `gorm:"type:text;not null"`
+	BudgetMin   int       `gorm:"not null"`
+	BudgetMax   int       `gorm:"not null"`
+	Currency    string    `gorm:"type:varchar(3);not null"`
+	Deadline    time.Time `gorm:"not null;index"`                                 // Applications close after this
+	Status      string    `gorm:"type:varchar(20);not null;default:'open';index"` // open, closed
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+}
+
+// GigApplication model - a creative applying to a gig, with portfolio
+// projects as work samples
+type GigApplication struct {
+	ID          uint                    `gorm:"primaryKey"`
+	GigID       uint                    `gorm:"not null;uniqueIndex:idx_gig_applicant"`
+	Gig         Gig                     `gorm:"foreignKey:GigID"`
+	ApplicantID uint                    `gorm:"not null;uniqueIndex:idx_gig_applicant;index"`
+	Applicant   User                    `gorm:"foreignKey:ApplicantID"`
+	CoverNote   string                  `gorm:"type:text"`
+	Status      string                  `gorm:"type:varchar(20);not null;default:'submitted';index"` // submitted, shortlisted, accepted, rejected
+	Projects    []GigApplicationProject `gorm:"foreignKey:ApplicationID"`
+	CreatedAt   time.Time               `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time               `gorm:"autoUpdateTime"`
+}
+
+// GigApplicationProject model - a portfolio project attached to an application
+type GigApplicationProject struct {
+	ApplicationID uint    `gorm:"primaryKey"`
+	ProjectID     uint    `gorm:"primaryKey;index"`
+	Project       Project `gorm:"foreignKey:ProjectID"`
+	Order         int     `gorm:"default:0"`
+}
+
+// AvailabilityWindow model - a date range in which a creative is available or
+// already booked
+type AvailabilityWindow struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	StartDate time.Time `gorm:"type:date;not null"`
+	EndDate   time.Time `gorm:"type:date;not null"`        // Inclusive
+	Status    string    `gorm:"type:varchar(20);not null"` // available, booked
+	Note      string    `gorm:"type:varchar(255)"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// RateCard model - a creative's published rate for one kind of engagement
+type RateCard struct {
+	ID          uint      `gorm:"primaryKey"`
+	UserID      uint      `gorm:"not null;uniqueIndex:idx_rate_card_unit"`
+	Unit        string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_rate_card_unit"` // hourly, day, project
+	Amount      int       `gorm:"not null"`
+	Currency    string    `gorm:"type:varchar(3);not null;uniqueIndex:idx_rate_card_unit"`
+	Description string    `gorm:"type:varchar(255)"` // e.g. "Logo and brand guide"
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+}
+
+// ProjectRevision model - a project's content after each edit, so edits can
+// be compared and rolled back
+type ProjectRevision struct {
+	ID           uint      `gorm:"primaryKey"`
+	ProjectID    uint      `gorm:"not null;uniqueIndex:idx_project_revision"`
+	Number       int       `gorm:"not null;uniqueIndex:idx_project_revision"` // Counts up from 1 per project
+	AuthorID     uint      `gorm:"not null"`
+	Author       User      `gorm:"foreignKey:AuthorID"`
+	Title        string    `gorm:"type:varchar(255);not null"`
+	Description  string    `gorm:"type:text;not null"`
+	Tags         string    `gorm:"type:text"`
+	ImageURLs    string    `gorm:"type:jsonb;not null;default:'[]'"` // JSON array in display order, cover first
+	RestoredFrom *int      // Revision a rollback brought back
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+}
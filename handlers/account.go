@@ -0,0 +1,521 @@
+package handlers
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"jobconnect-backend/config"
+	"jobconnect-backend/models"
+	"jobconnect-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// accountDeletionGracePeriod is how long a user can change their mind before
+// the account is purged
+const accountDeletionGracePeriod = 14 * 24 * time.Hour
+
+// ExportMyData - Download everything we hold about the current user, as a ZIP
+// of JSON files (default) or a single JSON document with ?format=json
+func ExportMyData(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	export, err := buildDataExport(userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export data"})
+		return
+	}
+
+	utils.RecordAudit(c, "account.export", "user", userID, nil)
+
+	filename := fmt.Sprintf("rosyartgrid-export-%d-%s", export.Profile.ID, export.ExportedAt.Format("20060102"))
+
+	if c.Query("format") == "json" {
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`.json"`)
+		c.JSON(http.StatusOK, export)
+		return
+	}
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`.zip"`)
+	c.Status(http.StatusOK)
+
+	archive := zip.NewWriter(c.Writer)
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", export.Profile},
+		{"availability.json", export.Availability},
+		{"rates.json", export.Rates},
+		{"projects.json", export.Projects},
+		{"comments.json", export.Comments},
+		{"likes.json", export.Likes},
+		{"followers.json", export.Followers},
+		{"following.json", export.Following},
+		{"inquiries.json", export.Inquiries},
+		{"gig_applications.json", export.Applications},
+	}
+	for _, file := range files {
+		w, err := archive.Create(file.name)
+		if err != nil {
+			log.Println("Warning: Failed to write data export:", err)
+			return
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			log.Println("Warning: Failed to write data export:", err)
+			return
+		}
+	}
+	if err := archive.Close(); err != nil {
+		log.Println("Warning: Failed to write data export:", err)
+	}
+}
+
+func buildDataExport(userID uint) (models.DataExport, error) {
+	var user config.User
+	if err := config.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		return models.DataExport{}, err
+	}
+
+	export := models.DataExport{
+		ExportedAt: time.Now().UTC(),
+		Profile: models.ExportProfile{
+			ID:          user.ID,
+			Username:    userHandle(user),
+			Name:        user.Name,
+			Email:       user.Email,
+			Role:        user.Role,
+			Bio:         user.Bio,
+			Location:    user.Location,
+			Skills:      user.Skills,
+			Website:     user.Website,
+			BehanceURL:  user.BehanceURL,
+			DribbbleURL: user.DribbbleURL,
+			LinkedInURL: user.LinkedInURL,
+			TwitterURL:  user.TwitterURL,
+			AvatarURL:   user.AvatarURL,
+			ForHire:     user.ForHire,
+			Verified:    user.Verified,
+			CreatedAt:   user.CreatedAt,
+		},
+		Availability: []models.AvailabilityResponse{},
+		Rates:        rateCardsOf(userID),
+		Projects:     []models.ExportProject{},
+		Comments:     []models.ExportComment{},
+		Likes:        []models.ExportLike{},
+		Followers:    []models.ExportFollow{},
+		Following:    []models.ExportFollow{},
+		Inquiries:    []models.InquiryResponse{},
+		Applications: []models.ExportGigApplication{},
+	}
+
+	var windows []config.AvailabilityWindow
+	if err := config.DB.Where("user_id = ?", userID).Order("start_date").Find(&windows).Error; err != nil {
+		return export, err
+	}
+	for _, window := range windows {
+		export.Availability = append(export.Availability, availabilityResponse(window))
+	}
+
+	// Projects, including ones removed by moderators
+	var projects []config.Project
+	if err := config.DB.Preload("Category").Preload("Images", func(db *gorm.DB) *gorm.DB {
+		return db.Order(`project_images."order"`)
+	}).Where("user_id = ?", userID).Order("created_at").Find(&projects).Error; err != nil {
+		return export, err
+	}
+	for _, project := range projects {
+		imageURLs := []string{}
+		for _, img := range project.Images {
+			imageURLs = append(imageURLs, img.ImageURL)
+		}
+		export.Projects = append(export.Projects, models.ExportProject{
+			ID:          project.ID,
+			Title:       project.Title,
+			Description: project.Description,
+			Category:    project.Category.Name,
+			Tags:        project.Tags,
+			CoverImage:  project.CoverImage,
+			ImageURLs:   imageURLs,
+			Views:       project.Views,
+			LikesCount:  project.LikesCount,
+			Status:      project.Status,
+			PublishAt:   project.PublishAt,
+			CreatedAt:   project.CreatedAt,
+			UpdatedAt:   project.UpdatedAt,
+			DeletedAt:   project.DeletedAt,
+		})
+	}
+
+	var comments []config.Comment
+	if err := config.DB.Where("user_id = ?", userID).Order("created_at").Find(&comments).Error; err != nil {
+		return export, err
+	}
+	for _, comment := range comments {
+		export.Comments = append(export.Comments, models.ExportComment{
+			ID:        comment.ID,
+			ProjectID: comment.ProjectID,
+			Content:   comment.Content,
+			CreatedAt: comment.CreatedAt,
+		})
+	}
+
+	var likes []config.Like
+	if err := config.DB.Where("user_id = ?", userID).Order("created_at").Find(&likes).Error; err != nil {
+		return export, err
+	}
+	for _, like := range likes {
+		export.Likes = append(export.Likes, models.ExportLike{
+			ProjectID: like.ProjectID,
+			CreatedAt: like.CreatedAt,
+		})
+	}
+
+	var followers []config.Follow
+	if err := config.DB.Preload("Follower").Where("following_id = ?", userID).Order("created_at").Find(&followers).Error; err != nil {
+		return export, err
+	}
+	for _, follow := range followers {
+		export.Followers = append(export.Followers, models.ExportFollow{
+			UserID:    follow.Follower.ID,
+			Name:      follow.Follower.Name,
+			CreatedAt: follow.CreatedAt,
+		})
+	}
+
+	var following []config.Follow
+	if err := config.DB.Preload("Following").Where("follower_id = ?", userID).Order("created_at").Find(&following).Error; err != nil {
+		return export, err
+	}
+	for _, follow := range following {
+		export.Following = append(export.Following, models.ExportFollow{
+			UserID:    follow.Following.ID,
+			Name:      follow.Following.Name,
+			CreatedAt: follow.CreatedAt,
+		})
+	}
+
+	var inquiries []config.HireInquiry
+	if err := config.DB.Preload("Company").Preload("Sender").Preload("Creative").
+		Where("creative_id = ?", userID).Order("created_at").Find(&inquiries).Error; err != nil {
+		return export, err
+	}
+	for _, inquiry := range inquiries {
+		response := inquiryResponse(inquiry)
+		response.History = inquiryHistory(inquiry.ID)
+		export.Inquiries = append(export.Inquiries, response)
+	}
+
+	var applications []config.GigApplication
+	if err := config.DB.Preload("Gig.Company").Preload("Projects", func(db *gorm.DB) *gorm.DB {
+		return db.Order(`gig_application_projects."order"`)
+	}).Where("applicant_id = ?", userID).Order("created_at").Find(&applications).Error; err != nil {
+		return export, err
+	}
+	for _, application := range applications {
+		projectIDs := []uint{}
+		for _, link := range application.Projects {
+			projectIDs = append(projectIDs, link.ProjectID)
+		}
+		export.Applications = append(export.Applications, models.ExportGigApplication{
+			ID:         application.ID,
+			GigID:      application.GigID,
+			GigTitle:   application.Gig.Title,
+			Company:    application.Gig.Company.Name,
+			CoverNote:  application.CoverNote,
+			Status:     application.Status,
+			ProjectIDs: projectIDs,
+			CreatedAt:  application.CreatedAt,
+			UpdatedAt:  application.UpdatedAt,
+		})
+	}
+
+	return export, nil
+}
+
+// accountDeletionTokenTTL is how long an emailed deletion link stays valid
+const accountDeletionTokenTTL = time.Hour
+
+// RequestAccountDeletion - Schedule the current account for deletion after
+// the grace period. Accounts confirm with their password; without one (social
+// logins never chose a password) a confirmation link is emailed instead.
+func RequestAccountDeletion(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	// The body is optional
+	var req models.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user config.User
+	if err := config.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if user.DeletionScheduledAt != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Account deletion is already scheduled"})
+		return
+	}
+
+	if req.Password == "" {
+		token, err := utils.GenerateActionToken(user.ID, user.Email, utils.PurposeDeleteAccount, accountDeletionTokenTTL)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send confirmation"})
+			return
+		}
+		body := fmt.Sprintf(
+			"Hi %s,\n\nSomeone asked to delete your RosyArtGrid account. If it was you, confirm here:\n\n%s\n\nThis link expires in %d minutes. If it wasn't you, ignore this email and consider changing your password.",
+			user.Name, utils.FrontendLink("/confirm-account-deletion", token), int(accountDeletionTokenTTL.Minutes()),
+		)
+		if err := utils.GetMailer().Send(user.Email, "Confirm your RosyArtGrid account deletion", body); err != nil {
+			log.Println("Warning: Failed to send account deletion confirmation:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send confirmation"})
+			return
+		}
+
+		c.JSON(http.StatusAccepted, gin.H{
+			"success": true,
+			"message": "Check your email to confirm the account deletion",
+		})
+		return
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Password is incorrect"})
+		return
+	}
+
+	scheduleAccountDeletion(c, user)
+}
+
+// ConfirmAccountDeletion - Schedule the deletion with the link emailed by
+// RequestAccountDeletion
+func ConfirmAccountDeletion(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.ConfirmAccountDeletionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims, err := utils.ValidateActionToken(req.Token, utils.PurposeDeleteAccount)
+	if err != nil || claims.UserID != userID.(uint) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired confirmation link"})
+		return
+	}
+
+	// The link stops working if the address changed since it was sent
+	var user config.User
+	if err := config.DB.Where("id = ? AND email = ?", claims.UserID, claims.Email).First(&user).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired confirmation link"})
+		return
+	}
+	if user.DeletionScheduledAt != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Account deletion is already scheduled"})
+		return
+	}
+
+	scheduleAccountDeletion(c, user)
+}
+
+// scheduleAccountDeletion starts the grace period of a confirmed deletion
+func scheduleAccountDeletion(c *gin.Context, user config.User) {
+	scheduledAt := time.Now().Add(accountDeletionGracePeriod)
+	if err := config.DB.Model(&user).Update("deletion_scheduled_at", scheduledAt).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to schedule account deletion"})
+		return
+	}
+
+	body := fmt.Sprintf(
+		"Hi %s,\n\nYour RosyArtGrid account will be deleted on %s.\n\nIf you change your mind, sign in and cancel the deletion before then.",
+		user.Name, scheduledAt.UTC().Format(time.RFC1123),
+	)
+	if err := utils.GetMailer().Send(user.Email, "Your RosyArtGrid account will be deleted", body); err != nil {
+		log.Println("Warning: Failed to send account deletion email:", err)
+	}
+
+	utils.RecordAudit(c, "account.delete_requested", "user", user.ID, utils.AuditDiff{
+		"deletion_scheduled_at": {From: nil, To: scheduledAt},
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":               true,
+		"message":               "Account scheduled for deletion",
+		"deletion_scheduled_at": scheduledAt,
+	})
+}
+
+// CancelAccountDeletion - Keep the account during the grace period
+func CancelAccountDeletion(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	result := config.DB.Model(&config.User{}).
+		Where("id = ? AND deletion_scheduled_at IS NOT NULL", userID).
+		Update("deletion_scheduled_at", nil)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel account deletion"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No account deletion is scheduled"})
+		return
+	}
+
+	utils.RecordAudit(c, "account.delete_cancelled", "user", userID, nil)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Account deletion cancelled",
+	})
+}
+
+// RunAccountPurger deletes accounts whose grace period has ended, checking
+// every interval. It blocks, so start it in its own goroutine.
+func RunAccountPurger(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		var users []config.User
+		config.DB.Where("deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ?", time.Now()).Find(&users)
+		for _, user := range users {
+			if err := purgeAccount(user); err != nil {
+				log.Printf("Warning: Failed to delete account %d: %v", user.ID, err)
+				continue
+			}
+			utils.RecordSystemAudit("account.deleted", "user", user.ID, nil)
+		}
+		<-ticker.C
+	}
+}
+
+// purgeAccount removes a user's projects, likes, follows and credentials and
+// scrubs the user row. The row itself stays as an anonymous tombstone so the
+// user's comments on other people's work remain, attributed to "Deleted user".
+func purgeAccount(user config.User) error {
+	var projectIDs []uint
+	config.DB.Model(&config.Project{}).Where("user_id = ?", user.ID).Pluck("id", &projectIDs)
+
+	var imageURLs []string
+	if len(projectIDs) > 0 {
+		// Including images only earlier revisions still refer to
+		config.DB.Raw(`SELECT image_url FROM project_images WHERE project_id IN ?
+			UNION SELECT jsonb_array_elements_text(image_urls) FROM project_revisions WHERE project_id IN ?`, projectIDs, projectIDs).
+			Scan(&imageURLs)
+	}
+	if user.AvatarURL != "" {
+		imageURLs = append(imageURLs, user.AvatarURL)
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		for _, projectID := range projectIDs {
+			if err := purgeProject(tx, projectID); err != nil {
+				return err
+			}
+		}
+
+		// Drop likes and fix the counters of the projects they were on
+		var likedProjectIDs []uint
+		if err := tx.Model(&config.Like{}).Where("user_id = ?", user.ID).Pluck("project_id", &likedProjectIDs).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", user.ID).Delete(&config.Like{}).Error; err != nil {
+			return err
+		}
+		if len(likedProjectIDs) > 0 {
+			if err := tx.Exec(`UPDATE projects SET likes_count = (SELECT COUNT(*) FROM likes WHERE likes.project_id = projects.id)
+				WHERE id IN ?`, likedProjectIDs).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("follower_id = ? OR following_id = ?", user.ID, user.ID).Delete(&config.Follow{}).Error; err != nil {
+			return err
+		}
+
+		// Recruiters' notes about the user are personal data too
+		if err := tx.Where("entry_id IN (SELECT id FROM shortlist_entries WHERE candidate_id = ?)", user.ID).Delete(&config.ShortlistNote{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("candidate_id = ?", user.ID).Delete(&config.ShortlistEntry{}).Error; err != nil {
+			return err
+		}
+
+		// Companies keep a record of the inquiries they sent, without what was
+		// said in them
+		if err := tx.Model(&config.HireInquiryEvent{}).Where("inquiry_id IN (SELECT id FROM hire_inquiries WHERE creative_id = ?)", user.ID).
+			Updates(map[string]interface{}{"message": "", "timeline": ""}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&config.HireInquiry{}).Where("creative_id = ?", user.ID).
+			Updates(map[string]interface{}{"title": "Inquiry to a deleted user", "brief": "", "timeline": ""}).Error; err != nil {
+			return err
+		}
+
+		// Gig applications, with the work samples attached to them
+		if err := tx.Where("application_id IN (SELECT id FROM gig_applications WHERE applicant_id = ?)", user.ID).Delete(&config.GigApplicationProject{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("applicant_id = ?", user.ID).Delete(&config.GigApplication{}).Error; err != nil {
+			return err
+		}
+
+		// A company the user owned passes to its longest-standing member
+		if err := tx.Exec(`UPDATE companies SET owner_id = (
+				SELECT id FROM users WHERE company_id = companies.id AND id <> ? ORDER BY created_at LIMIT 1
+			) WHERE owner_id = ?`, user.ID, user.ID).Error; err != nil {
+			return err
+		}
+
+		for _, model := range []interface{}{&config.Session{}, &config.APIKey{}, &config.UserIdentity{}, &config.RecoveryCode{}, &config.PasswordResetToken{}, &config.HandleRedirect{}, &config.AvailabilityWindow{}, &config.RateCard{}} {
+			if err := tx.Where("user_id = ?", user.ID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&user).Updates(map[string]interface{}{
+			"name":                  "Deleted user",
+			"username":              nil,
+			"email":                 fmt.Sprintf("deleted-%d@users.invalid", user.ID),
+			"password":              "",
+			"bio":                   "",
+			"location":              "",
+			"skills":                "",
+			"website":               "",
+			"behance_url":           "",
+			"dribbble_url":          "",
+			"linked_in_url":         "",
+			"twitter_url":           "",
+			"avatar_url":            "",
+			"for_hire":              false,
+			"verified":              false,
+			"company_id":            nil,
+			"totp_secret":           "",
+			"totp_enabled":          false,
+			"status":                utils.StatusDeleted,
+			"status_reason":         "",
+			"deletion_scheduled_at": nil,
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	deleteUserImages(user.ID, imageURLs)
+	return nil
+}
@@ -0,0 +1,147 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"jobconnect-backend/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func deletionScheduled(t *testing.T, userID uint) bool {
+	t.Helper()
+
+	var user config.User
+	if err := config.DB.First(&user, userID).Error; err != nil {
+		t.Fatal(err)
+	}
+	return user.DeletionScheduledAt != nil
+}
+
+func TestSocialAccountDeletesByEmailConfirmation(t *testing.T) {
+	r, mailer := setupServer(t)
+	provider := newMockOIDC(t)
+
+	// Signed up with the provider, so there's no password to confirm with
+	status, body := oidcLogin(t, r, provider, jwt.MapClaims{"sub": "mock-grace", "email": "grace@example.com", "email_verified": true})
+	if status != http.StatusOK {
+		t.Fatalf("login: got %d %v", status, body)
+	}
+	userID := loggedInUserID(t, body)
+	token := body["token"].(string)
+
+	status, body = doJSON(t, r, http.MethodDelete, "/api/profile", token, nil)
+	if status != http.StatusAccepted {
+		t.Fatalf("request deletion: got %d %v", status, body)
+	}
+	if deletionScheduled(t, userID) {
+		t.Fatal("deletion scheduled before it was confirmed")
+	}
+	link := mailedToken(t, mailer, "grace@example.com")
+
+	// Another user can't use the link
+	register(t, r, "eve@example.com")
+	status, _ = doJSON(t, r, http.MethodPost, "/api/profile/confirm-deletion", login(t, r, "eve@example.com"), map[string]string{"token": link})
+	if status != http.StatusBadRequest {
+		t.Fatalf("someone else's link: got %d, want 400", status)
+	}
+
+	status, body = doJSON(t, r, http.MethodPost, "/api/profile/confirm-deletion", token, map[string]string{"token": link})
+	if status != http.StatusOK {
+		t.Fatalf("confirm deletion: got %d %v", status, body)
+	}
+	if !deletionScheduled(t, userID) {
+		t.Fatal("deletion not scheduled")
+	}
+
+	status, _ = doJSON(t, r, http.MethodPost, "/api/profile/confirm-deletion", token, map[string]string{"token": link})
+	if status != http.StatusBadRequest {
+		t.Fatalf("confirming twice: got %d, want 400", status)
+	}
+}
+
+func TestAccountDeletionWithPassword(t *testing.T) {
+	r, _ := setupServer(t)
+
+	userID := register(t, r, "ada@example.com")
+	token := login(t, r, "ada@example.com")
+
+	status, _ := doJSON(t, r, http.MethodDelete, "/api/profile", token, map[string]string{"password": "wrong"})
+	if status != http.StatusUnauthorized {
+		t.Fatalf("wrong password: got %d, want 401", status)
+	}
+	status, body := doJSON(t, r, http.MethodDelete, "/api/profile", token, map[string]string{"password": "secret123"})
+	if status != http.StatusOK || !deletionScheduled(t, userID) {
+		t.Fatalf("right password: got %d %v", status, body)
+	}
+}
+
+func TestExportIncludesInquiries(t *testing.T) {
+	r, _ := setupServer(t)
+
+	senderID, _ := recruiter(t, r, "owner@example.com")
+	company := config.Company{Name: "Acme", OwnerID: &senderID}
+	config.DB.Create(&company)
+	adaID, adaToken := creative(t, r, "ada@example.com")
+
+	inquiry := config.HireInquiry{
+		CompanyID:  company.ID,
+		SenderID:   senderID,
+		CreativeID: adaID,
+		Title:      "Album cover",
+		Brief:      "Twelve inches, hand-drawn",
+		BudgetMin:  500,
+		BudgetMax:  800,
+		Currency:   "EUR",
+		Timeline:   "By June",
+		Status:     "countered",
+	}
+	config.DB.Create(&inquiry)
+	config.DB.Create(&config.HireInquiryEvent{InquiryID: inquiry.ID, ActorID: adaID, Status: "countered", Message: "Could we say 900?"})
+
+	status, body := doJSON(t, r, http.MethodGet, "/api/profile/export?format=json", adaToken, nil)
+	if status != http.StatusOK {
+		t.Fatalf("export: got %d %v", status, body)
+	}
+	inquiries := body["inquiries"].([]interface{})
+	if len(inquiries) != 1 {
+		t.Fatalf("expected 1 inquiry, got %v", inquiries)
+	}
+	exported := inquiries[0].(map[string]interface{})
+	history := exported["history"].([]interface{})
+	if exported["brief"] != inquiry.Brief || len(history) != 1 || history[0].(map[string]interface{})["message"] != "Could we say 900?" {
+		t.Fatalf("inquiry exported without its details: %v", exported)
+	}
+}
+
+func TestExportIncludesGigApplications(t *testing.T) {
+	r, _ := setupServer(t)
+
+	posterID, _ := recruiter(t, r, "owner@example.com")
+	company := config.Company{Name: "Acme", OwnerID: &posterID}
+	config.DB.Create(&company)
+	gig := config.Gig{CompanyID: company.ID, PostedByID: posterID, CategoryID: 1, Title: "Mural", Description: "A wall", BudgetMin: 100, BudgetMax: 200, Currency: "EUR", Deadline: time.Now().Add(24 * time.Hour)}
+	config.DB.Create(&gig)
+
+	adaID, adaToken := creative(t, r, "ada@example.com")
+	application := config.GigApplication{GigID: gig.ID, ApplicantID: adaID, CoverNote: "I paint walls", Status: "submitted"}
+	config.DB.Create(&application)
+	config.DB.Create(&config.GigApplicationProject{ApplicationID: application.ID, ProjectID: 7, Order: 1})
+	config.DB.Create(&config.GigApplicationProject{ApplicationID: application.ID, ProjectID: 3, Order: 0})
+
+	status, body := doJSON(t, r, http.MethodGet, "/api/profile/export?format=json", adaToken, nil)
+	if status != http.StatusOK {
+		t.Fatalf("export: got %d %v", status, body)
+	}
+	applications := body["gig_applications"].([]interface{})
+	if len(applications) != 1 {
+		t.Fatalf("expected 1 application, got %v", applications)
+	}
+	exported := applications[0].(map[string]interface{})
+	projectIDs := exported["project_ids"].([]interface{})
+	if exported["cover_note"] != "I paint walls" || exported["gig_title"] != "Mural" || len(projectIDs) != 2 || projectIDs[0].(float64) != 3 {
+		t.Fatalf("application exported without its details: %v", exported)
+	}
+}
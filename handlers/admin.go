@@ -0,0 +1,528 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"jobconnect-backend/config"
+	"jobconnect-backend/models"
+	"jobconnect-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// UnlockUser - Admin clears a login lockout on an account
+func UnlockUser(c *gin.Context) {
+	userID := c.Param("id")
+
+	var user config.User
+	if err := config.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if err := utils.GetLoginLimiter().Unlock(user.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unlock account"})
+		return
+	}
+
+	utils.RecordAudit(c, "user.unlock", "user", user.ID, nil)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Account unlocked",
+	})
+}
+
+// GetRolePermissions - Admin lists the permissions granted to each role
+func GetRolePermissions(c *gin.Context) {
+	var rows []config.RolePermission
+	config.DB.Order("role, permission").Find(&rows)
+
+	roles := make(map[string][]string)
+	for _, row := range rows {
+		roles[row.Role] = append(roles[row.Role], row.Permission)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"roles":       roles,
+		"permissions": utils.AllPermissions,
+	})
+}
+
+// SetRolePermissions - Admin replaces the permission set of a role
+func SetRolePermissions(c *gin.Context) {
+	role := c.Param("role")
+
+	var req struct {
+		Permissions []string `json:"permissions"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	for _, perm := range req.Permissions {
+		if !utils.ValidPermission(perm) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown permission: " + perm})
+			return
+		}
+	}
+
+	// Stop admins from locking everyone out of permission management
+	if role == "admin" && !containsString(req.Permissions, utils.PermRoleManage) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The admin role must keep " + utils.PermRoleManage})
+		return
+	}
+
+	var previous []string
+	config.DB.Model(&config.RolePermission{}).Where("role = ?", role).Order("permission").Pluck("permission", &previous)
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role = ?", role).Delete(&config.RolePermission{}).Error; err != nil {
+			return err
+		}
+		for _, perm := range req.Permissions {
+			if err := tx.Create(&config.RolePermission{Role: role, Permission: perm}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update permissions"})
+		return
+	}
+
+	utils.InvalidatePermissionCache()
+	utils.RecordAudit(c, "role.permissions_update", "role", role, utils.AuditDiff{
+		"permissions": {From: previous, To: req.Permissions},
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Permissions updated",
+	})
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// AdminGetUsers - Admin lists and searches users
+func AdminGetUsers(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset := (page - 1) * limit
+
+	role := c.Query("role")
+	status := c.Query("status")
+	search := c.Query("search")
+
+	var users []config.User
+	var totalCount int64
+
+	query := config.DB.Model(&config.User{})
+
+	if role != "" {
+		query = query.Where("role = ?", role)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if search != "" {
+		query = query.Where("name ILIKE ? OR email ILIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+
+	query.Count(&totalCount)
+	query.Preload("Company").Offset(offset).Limit(limit).Order("created_at DESC").Find(&users)
+
+	var response []models.UserResponse
+	for _, user := range users {
+		userResp := models.UserResponse{
+			ID:        user.ID,
+			Name:      user.Name,
+			Email:     user.Email,
+			Role:      user.Role,
+			Location:  user.Location,
+			AvatarURL: user.AvatarURL,
+			Verified:  user.Verified,
+			Status:    user.Status,
+			CreatedAt: user.CreatedAt,
+		}
+
+		if user.Company != nil {
+			userResp.Company = &models.CompanyResponse{
+				ID:   user.Company.ID,
+				Name: user.Company.Name,
+			}
+		}
+
+		response = append(response, userResp)
+	}
+
+	totalPages := int((totalCount + int64(limit) - 1) / int64(limit))
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"users":      response,
+		"page":       page,
+		"totalPages": totalPages,
+		"totalCount": totalCount,
+	})
+}
+
+// ChangeUserRole - Admin changes a user's role
+func ChangeUserRole(c *gin.Context) {
+	adminID, _ := c.Get("user_id")
+	userID := c.Param("id")
+
+	var req models.ChangeRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user config.User
+	if err := config.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if user.ID == adminID.(uint) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot change your own role"})
+		return
+	}
+
+	previousRole := user.Role
+	if err := config.DB.Model(&user).Update("role", req.Role).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to change role"})
+		return
+	}
+
+	// Access tokens carry the role, so force the user to pick up the new one
+	revokeUserSessions(user.ID)
+
+	utils.RecordAudit(c, "user.role_change", "user", user.ID, utils.AuditDiff{
+		"role": {From: previousRole, To: req.Role},
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Role updated",
+	})
+}
+
+// setUserStatus moves an account to a moderation status, records who did it
+// and why, and signs the account out
+func setUserStatus(c *gin.Context, status, reason string, suspendedUntil *time.Time) {
+	adminID, _ := c.Get("user_id")
+	userID := c.Param("id")
+
+	var user config.User
+	if err := config.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if user.ID == adminID.(uint) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot change your own account status"})
+		return
+	}
+
+	previous := user
+	now := time.Now()
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"status":            status,
+			"suspended_until":   suspendedUntil,
+			"status_reason":     reason,
+			"status_changed_by": adminID,
+			"status_changed_at": now,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&config.AccountStatusChange{
+			UserID:         user.ID,
+			Status:         status,
+			Reason:         reason,
+			SuspendedUntil: suspendedUntil,
+			ActorID:        adminID.(uint),
+		}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update account status"})
+		return
+	}
+
+	if status != utils.StatusActive {
+		revokeUserSessions(user.ID)
+	}
+
+	utils.RecordAudit(c, "user.status_change", "user", user.ID, utils.AuditDiff{
+		"status":          {From: previous.Status, To: status},
+		"suspended_until": {From: previous.SuspendedUntil, To: suspendedUntil},
+		"reason":          {From: previous.StatusReason, To: reason},
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":         true,
+		"message":         "Account status updated",
+		"status":          status,
+		"suspended_until": suspendedUntil,
+	})
+}
+
+// SuspendUser - Admin suspends an account for a number of days
+func SuspendUser(c *gin.Context) {
+	var req models.SuspendUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	until := time.Now().AddDate(0, 0, req.Days)
+	setUserStatus(c, utils.StatusSuspended, req.Reason, &until)
+}
+
+// BanUser - Admin bans an account permanently
+func BanUser(c *gin.Context) {
+	var req models.AccountStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	setUserStatus(c, utils.StatusBanned, req.Reason, nil)
+}
+
+// ReinstateUser - Admin lifts a suspension or ban
+func ReinstateUser(c *gin.Context) {
+	var req models.AccountStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	setUserStatus(c, utils.StatusActive, req.Reason, nil)
+}
+
+// GetUserStatusHistory - Admin views an account's moderation history
+func GetUserStatusHistory(c *gin.Context) {
+	userID := c.Param("id")
+
+	var changes []config.AccountStatusChange
+	config.DB.Preload("Actor").Where("user_id = ?", userID).Order("created_at DESC").Find(&changes)
+
+	var response []models.AccountStatusChangeResponse
+	for _, change := range changes {
+		response = append(response, models.AccountStatusChangeResponse{
+			ID:             change.ID,
+			Status:         change.Status,
+			Reason:         change.Reason,
+			SuspendedUntil: change.SuspendedUntil,
+			Actor: models.UserResponse{
+				ID:    change.Actor.ID,
+				Name:  change.Actor.Name,
+				Email: change.Actor.Email,
+			},
+			CreatedAt: change.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"history": response,
+	})
+}
+
+// FeatureProject - Admin features or un-features a project
+func FeatureProject(c *gin.Context) {
+	projectID := c.Param("id")
+
+	var req models.FeatureProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var project config.Project
+	if err := config.DB.Where("id = ? AND deleted_at IS NULL", projectID).First(&project).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+
+	previous := project.Featured
+	if err := config.DB.Model(&project).Update("featured", *req.Featured).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update project"})
+		return
+	}
+
+	utils.RecordAudit(c, "project.feature", "project", project.ID, utils.AuditDiff{
+		"featured": {From: previous, To: *req.Featured},
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"featured": *req.Featured,
+	})
+}
+
+// AdminDeleteProject - Admin soft-deletes a project, or removes it for good with ?hard=true
+func AdminDeleteProject(c *gin.Context) {
+	projectID := c.Param("id")
+	hard := c.Query("hard") == "true"
+
+	var project config.Project
+	if err := config.DB.Where("id = ?", projectID).First(&project).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+
+	var err error
+	if hard {
+		err = config.DB.Transaction(func(tx *gorm.DB) error {
+			return purgeProject(tx, project.ID)
+		})
+	} else {
+		err = config.DB.Model(&project).Update("deleted_at", time.Now()).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete project"})
+		return
+	}
+
+	utils.RecordAudit(c, "project.delete", "project", project.ID, utils.AuditDiff{
+		"title": {From: project.Title, To: nil},
+		"hard":  {From: nil, To: hard},
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Project deleted",
+	})
+}
+
+// RestoreProject - Admin restores a soft-deleted project
+func RestoreProject(c *gin.Context) {
+	projectID := c.Param("id")
+
+	result := config.DB.Model(&config.Project{}).Where("id = ? AND deleted_at IS NOT NULL", projectID).Update("deleted_at", nil)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore project"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Deleted project not found"})
+		return
+	}
+
+	utils.RecordAudit(c, "project.restore", "project", projectID, nil)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Project restored",
+	})
+}
+
+// AdminDeleteComment - Admin soft-deletes a comment, or removes it for good with ?hard=true
+func AdminDeleteComment(c *gin.Context) {
+	commentID := c.Param("id")
+
+	var result *gorm.DB
+	if c.Query("hard") == "true" {
+		result = config.DB.Where("id = ?", commentID).Delete(&config.Comment{})
+	} else {
+		result = config.DB.Model(&config.Comment{}).Where("id = ? AND deleted_at IS NULL", commentID).Update("deleted_at", time.Now())
+	}
+
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return
+	}
+
+	utils.RecordAudit(c, "comment.delete", "comment", commentID, utils.AuditDiff{
+		"hard": {From: nil, To: c.Query("hard") == "true"},
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Comment deleted",
+	})
+}
+
+// GetDashboardStats - Admin dashboard: totals plus activity over the last ?days (default 30)
+func GetDashboardStats(c *gin.Context) {
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if days < 1 || days > 365 {
+		days = 30
+	}
+	since := time.Now().AddDate(0, 0, -days).Truncate(24 * time.Hour)
+
+	var totalUsers, totalProjects, totalComments, totalLikes int64
+	config.DB.Model(&config.User{}).Count(&totalUsers)
+	config.DB.Model(&config.Project{}).Where("deleted_at IS NULL").Count(&totalProjects)
+	config.DB.Model(&config.Comment{}).Where("deleted_at IS NULL").Count(&totalComments)
+	config.DB.Model(&config.Like{}).Count(&totalLikes)
+
+	usersByRole := []models.NamedCount{}
+	config.DB.Model(&config.User{}).
+		Select("role AS name, COUNT(*) AS count").
+		Group("role").Order("count DESC").
+		Scan(&usersByRole)
+
+	projectsByCategory := []models.NamedCount{}
+	config.DB.Table("categories").
+		Select("categories.name AS name, COUNT(projects.id) AS count").
+		Joins("LEFT JOIN projects ON projects.category_id = categories.id AND projects.deleted_at IS NULL").
+		Group("categories.name").Order("count DESC").
+		Scan(&projectsByCategory)
+
+	likesPerDay := []models.DailyCount{}
+	config.DB.Model(&config.Like{}).
+		Select("TO_CHAR(created_at, 'YYYY-MM-DD') AS day, COUNT(*) AS count").
+		Where("created_at >= ?", since).
+		Group("day").Order("day").
+		Scan(&likesPerDay)
+
+	commentsPerDay := []models.DailyCount{}
+	config.DB.Model(&config.Comment{}).
+		Select("TO_CHAR(created_at, 'YYYY-MM-DD') AS day, COUNT(*) AS count").
+		Where("created_at >= ? AND deleted_at IS NULL", since).
+		Group("day").Order("day").
+		Scan(&commentsPerDay)
+
+	viewsPerDay := []models.DailyCount{}
+	config.DB.Model(&config.ProjectDailyView{}).
+		Select("TO_CHAR(day, 'YYYY-MM-DD') AS day, SUM(views) AS count").
+		Where("day >= ?", since).
+		Group("project_daily_views.day").Order("day").
+		Scan(&viewsPerDay)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"stats": gin.H{
+			"totalUsers":         totalUsers,
+			"totalProjects":      totalProjects,
+			"totalComments":      totalComments,
+			"totalLikes":         totalLikes,
+			"usersByRole":        usersByRole,
+			"projectsByCategory": projectsByCategory,
+			"likesPerDay":        likesPerDay,
+			"commentsPerDay":     commentsPerDay,
+			"viewsPerDay":        viewsPerDay,
+		},
+	})
+}
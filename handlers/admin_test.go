@@ -0,0 +1,89 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"jobconnect-backend/config"
+)
+
+// admin registers an admin account and returns its token
+func admin(t *testing.T, r http.Handler, email string) string {
+	t.Helper()
+
+	userID := register(t, r, email)
+	config.DB.Model(&config.User{}).Where("id = ?", userID).Updates(map[string]interface{}{"role": "admin", "verified": true})
+	return login(t, r, email)
+}
+
+// publishedProject creates a published project for the user and returns its ID
+func publishedProject(t *testing.T, r http.Handler, userID uint, token string) uint {
+	t.Helper()
+
+	var category config.Category
+	config.DB.FirstOrCreate(&category, config.Category{Name: "Illustration", Slug: "illustration"})
+	status, body := doJSON(t, r, http.MethodPost, "/api/projects", token, map[string]interface{}{
+		"title":       "Work",
+		"description": "Some work",
+		"category_id": category.ID,
+		"image_urls":  []string{uploaded(t, userID, fmt.Sprintf("work-%d", time.Now().UnixNano()))},
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("create project: got %d %v", status, body)
+	}
+	return uint(body["project_id"].(float64))
+}
+
+func TestOwnerCantTouchTakenDownProject(t *testing.T) {
+	r, _ := setupServer(t)
+
+	adaID, adaToken := creative(t, r, "ada@example.com")
+	projectID := publishedProject(t, r, adaID, adaToken)
+	path := fmt.Sprintf("/api/projects/%d", projectID)
+
+	status, _ := doJSON(t, r, http.MethodDelete, "/api/admin/projects/"+fmt.Sprint(projectID), admin(t, r, "mod@example.com"), nil)
+	if status != http.StatusOK {
+		t.Fatalf("take down: got %d", status)
+	}
+
+	status, _ = doJSON(t, r, http.MethodPut, path, adaToken, map[string]string{"title": "Still here"})
+	if status != http.StatusNotFound {
+		t.Fatalf("editing a taken-down project: got %d, want 404", status)
+	}
+	status, _ = doJSON(t, r, http.MethodDelete, path, adaToken, nil)
+	if status != http.StatusNotFound {
+		t.Fatalf("purging a taken-down project: got %d, want 404", status)
+	}
+
+	var project config.Project
+	if err := config.DB.First(&project, projectID).Error; err != nil || project.Title != "Work" {
+		t.Fatalf("taken-down project changed: %+v, %v", project, err)
+	}
+}
+
+func TestFeatureProjectAuditsPreviousValue(t *testing.T) {
+	r, _ := setupServer(t)
+
+	adaID, adaToken := creative(t, r, "ada@example.com")
+	projectID := publishedProject(t, r, adaID, adaToken)
+	modToken := admin(t, r, "mod@example.com")
+
+	path := fmt.Sprintf("/api/admin/projects/%d/feature", projectID)
+	for _, featured := range []bool{true, true} {
+		if status, body := doJSON(t, r, http.MethodPut, path, modToken, map[string]bool{"featured": featured}); status != http.StatusOK {
+			t.Fatalf("feature: got %d %v", status, body)
+		}
+	}
+
+	var events []config.AuditEvent
+	config.DB.Where("action = ?", "project.feature").Order("id").Find(&events)
+	if len(events) != 2 {
+		t.Fatalf("expected 2 audit events, got %d", len(events))
+	}
+	if !strings.Contains(events[0].Changes, `"from":false`) || !strings.Contains(events[1].Changes, `"from":true`) {
+		t.Fatalf("previous value not audited: %s, %s", events[0].Changes, events[1].Changes)
+	}
+}
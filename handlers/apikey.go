@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"jobconnect-backend/config"
+	"jobconnect-backend/models"
+	"jobconnect-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxAPIKeysPerUser = 20
+
+// CreateAPIKey - Create a named, scoped API key. The key is only shown once.
+func CreateAPIKey(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	for _, scope := range req.Scopes {
+		if !utils.ValidScope(scope) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown scope: " + scope})
+			return
+		}
+	}
+	if req.ExpiresInDays < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expires_in_days must not be negative"})
+		return
+	}
+
+	var activeKeys int64
+	config.DB.Model(&config.APIKey{}).Where("user_id = ? AND revoked_at IS NULL", userID).Count(&activeKeys)
+	if activeKeys >= maxAPIKeysPerUser {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "API key limit reached, revoke an unused key first"})
+		return
+	}
+
+	rawKey, prefix, err := utils.GenerateAPIKey()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate API key"})
+		return
+	}
+
+	apiKey := config.APIKey{
+		UserID:  userID.(uint),
+		Name:    req.Name,
+		Prefix:  prefix,
+		KeyHash: utils.HashToken(rawKey),
+		Scopes:  strings.Join(req.Scopes, ","),
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		apiKey.ExpiresAt = &expiresAt
+	}
+
+	if err := config.DB.Create(&apiKey).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create API key"})
+		return
+	}
+
+	utils.RecordAudit(c, "api_key.create", "api_key", apiKey.ID, utils.AuditDiff{
+		"name":   {From: nil, To: apiKey.Name},
+		"scopes": {From: nil, To: apiKey.Scopes},
+	})
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "API key created, copy it now as it won't be shown again",
+		"key":     rawKey,
+		"api_key": apiKeyResponse(apiKey),
+	})
+}
+
+// GetMyAPIKeys - List the current user's active API keys
+func GetMyAPIKeys(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var apiKeys []config.APIKey
+	config.DB.Where("user_id = ? AND revoked_at IS NULL", userID).Order("created_at DESC").Find(&apiKeys)
+
+	var response []models.APIKeyResponse
+	for _, apiKey := range apiKeys {
+		response = append(response, apiKeyResponse(apiKey))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"api_keys": response,
+	})
+}
+
+// RevokeAPIKey - Revoke one of the current user's API keys
+func RevokeAPIKey(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	keyID := c.Param("id")
+
+	result := config.DB.Model(&config.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", keyID, userID).
+		Update("revoked_at", time.Now())
+
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found or unauthorized"})
+		return
+	}
+
+	utils.RecordAudit(c, "api_key.revoke", "api_key", keyID, nil)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "API key revoked",
+	})
+}
+
+func apiKeyResponse(apiKey config.APIKey) models.APIKeyResponse {
+	return models.APIKeyResponse{
+		ID:         apiKey.ID,
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Scopes:     strings.Split(apiKey.Scopes, ","),
+		ExpiresAt:  apiKey.ExpiresAt,
+		LastUsedAt: apiKey.LastUsedAt,
+		CreatedAt:  apiKey.CreatedAt,
+	}
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"jobconnect-backend/config"
+	"jobconnect-backend/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// auditQuery applies the filters shared by listing and export
+func auditQuery(c *gin.Context) *gorm.DB {
+	query := config.DB.Model(&config.AuditEvent{})
+
+	if actorID := c.Query("actor_id"); actorID != "" {
+		query = query.Where("actor_id = ?", actorID)
+	}
+	if action := c.Query("action"); action != "" {
+		query = query.Where("action = ?", action)
+	}
+	if targetType := c.Query("target_type"); targetType != "" {
+		query = query.Where("target_type = ?", targetType)
+	}
+	if targetID := c.Query("target_id"); targetID != "" {
+		query = query.Where("target_id = ?", targetID)
+	}
+	if from, err := time.Parse(time.RFC3339, c.Query("from")); err == nil {
+		query = query.Where("created_at >= ?", from)
+	}
+	if to, err := time.Parse(time.RFC3339, c.Query("to")); err == nil {
+		query = query.Where("created_at < ?", to)
+	}
+
+	return query
+}
+
+func auditEventResponse(event config.AuditEvent) models.AuditEventResponse {
+	return models.AuditEventResponse{
+		ID:         event.ID,
+		ActorID:    event.ActorID,
+		Action:     event.Action,
+		TargetType: event.TargetType,
+		TargetID:   event.TargetID,
+		IPAddress:  event.IPAddress,
+		UserAgent:  event.UserAgent,
+		Changes:    json.RawMessage(event.Changes),
+		CreatedAt:  event.CreatedAt,
+	}
+}
+
+// GetAuditEvents - Admin queries the audit log
+func GetAuditEvents(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset := (page - 1) * limit
+
+	var events []config.AuditEvent
+	var totalCount int64
+
+	query := auditQuery(c)
+	query.Count(&totalCount)
+	query.Offset(offset).Limit(limit).Order("created_at DESC, id DESC").Find(&events)
+
+	var response []models.AuditEventResponse
+	for _, event := range events {
+		response = append(response, auditEventResponse(event))
+	}
+
+	totalPages := int((totalCount + int64(limit) - 1) / int64(limit))
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"events":     response,
+		"page":       page,
+		"totalPages": totalPages,
+		"totalCount": totalCount,
+	})
+}
+
+// ExportAuditEvents - Admin downloads the filtered audit log as CSV or JSON (?format=)
+func ExportAuditEvents(c *gin.Context) {
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or json"})
+		return
+	}
+
+	rows, err := auditQuery(c).Order("created_at, id").Rows()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export audit log"})
+		return
+	}
+	defer rows.Close()
+
+	filename := "audit-" + time.Now().UTC().Format("20060102-150405") + "." + format
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+
+	// Stream rows so large exports don't have to fit in memory
+	if format == "json" {
+		c.Header("Content-Type", "application/x-ndjson")
+		enc := json.NewEncoder(c.Writer)
+		for rows.Next() {
+			var event config.AuditEvent
+			if err := config.DB.ScanRows(rows, &event); err != nil {
+				return
+			}
+			enc.Encode(auditEventResponse(event))
+		}
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"id", "created_at", "actor_id", "action", "target_type", "target_id", "ip_address", "user_agent", "changes"})
+	for rows.Next() {
+		var event config.AuditEvent
+		if err := config.DB.ScanRows(rows, &event); err != nil {
+			break
+		}
+		actorID := ""
+		if event.ActorID != nil {
+			actorID = strconv.FormatUint(uint64(*event.ActorID), 10)
+		}
+		w.Write([]string{
+			strconv.FormatUint(uint64(event.ID), 10),
+			event.CreatedAt.UTC().Format(time.RFC3339),
+			actorID,
+			event.Action,
+			event.TargetType,
+			event.TargetID,
+			event.IPAddress,
+			event.UserAgent,
+			event.Changes,
+		})
+	}
+	w.Flush()
+}
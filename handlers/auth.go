@@ -1,7 +1,12 @@
 package handlers
 
 import (
+	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"jobconnect-backend/config"
 	"jobconnect-backend/models"
@@ -43,7 +48,7 @@ func RegisterUser(c *gin.Context) {
 		Email:    req.Email,
 		Password: string(hashedPassword),
 		Role:     req.Role,
-		Verified: true,
+		Verified: false,
 	}
 
 	if err := config.DB.Create(&user).Error; err != nil {
@@ -51,23 +56,35 @@ func RegisterUser(c *gin.Context) {
 		return
 	}
 
-	// Generate JWT token
-	token, err := utils.GenerateToken(user.ID, user.Email, user.Role)
+	// Registration still succeeds if the mail can't be sent; the user can resend
+	if err := sendVerificationEmail(user); err != nil {
+		log.Println("Warning: Failed to send verification email:", err)
+	}
+
+	// Start a session and generate tokens
+	token, refreshToken, err := issueTokens(c, user)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
 	}
 
+	utils.RecordAuditAs(c, user.ID, "auth.register", "user", user.ID, utils.AuditDiff{
+		"role": {From: nil, To: user.Role},
+	})
+
 	c.JSON(http.StatusCreated, gin.H{
-		"success": true,
-		"message": "Registration successful",
-		"token":   token,
+		"success":       true,
+		"message":       "Registration successful, please check your email to verify your account",
+		"token":         token,
+		"refresh_token": refreshToken,
+		"expires_in":    int(utils.AccessTokenTTL.Seconds()),
 		"user": models.UserResponse{
 			ID:       user.ID,
 			Name:     user.Name,
 			Email:    user.Email,
 			Role:     user.Role,
 			Location: user.Location,
+			Verified: user.Verified,
 		},
 	})
 }
@@ -79,21 +96,79 @@ func LoginUser(c *gin.Context) {
 		return
 	}
 
+	// Refuse early while the account or IP is backing off
+	limiter := utils.GetLoginLimiter()
+	retryAfter, err := limiter.RetryAfter(req.Email, c.ClientIP())
+	if err != nil {
+		log.Println("Warning: Failed to check login attempts:", err)
+	}
+	if retryAfter > 0 {
+		tooManyLoginAttempts(c, retryAfter)
+		return
+	}
+
 	// Find user
 	var user config.User
 	if err := config.DB.Preload("Company").Where("email = ?", req.Email).First(&user).Error; err != nil {
+		recordLoginFailure(c, req.Email)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
 		return
 	}
 
 	// Check password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		recordLoginFailure(c, req.Email)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
 		return
 	}
 
-	// Generate JWT token
-	token, err := utils.GenerateToken(user.ID, user.Email, user.Role)
+	if err := limiter.RecordSuccess(req.Email); err != nil {
+		log.Println("Warning: Failed to reset login attempts:", err)
+	}
+
+	finishPrimaryAuth(c, user)
+}
+
+// finishPrimaryAuth continues a login once the first factor (password or
+// external identity) has been checked: 2FA users get a challenge, everyone
+// else a session.
+func finishPrimaryAuth(c *gin.Context, user config.User) {
+	if accountBlocked(c, user) {
+		return
+	}
+
+	// Second factor: hand out a short-lived challenge instead of a session
+	if user.TOTPEnabled {
+		sendTwoFactorChallenge(c, user, utils.PurposeLogin2FA)
+		return
+	}
+	if twoFactorEnrollmentRequired(user) {
+		sendTwoFactorChallenge(c, user, utils.PurposeEnroll2FA)
+		return
+	}
+
+	completeLogin(c, user, nil)
+}
+
+// accountBlocked answers 403 and reports true when the user is suspended or
+// banned and so may not log in
+func accountBlocked(c *gin.Context, user config.User) bool {
+	if !utils.AccountBlocked(user.Status, user.SuspendedUntil) {
+		return false
+	}
+	c.JSON(http.StatusForbidden, gin.H{
+		"error":  utils.AccountBlockedMessage(user.Status, user.SuspendedUntil),
+		"reason": user.StatusReason,
+	})
+	return true
+}
+
+// completeLogin starts a session for an authenticated user and writes the
+// login response. The user must have Company preloaded. Recovery codes are
+// included when 2FA was enrolled as part of this login.
+func completeLogin(c *gin.Context, user config.User, recoveryCodes []string) {
+	// Start a session and generate tokens
+	token, refreshToken, err := issueTokens(c, user)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
@@ -101,13 +176,16 @@ func LoginUser(c *gin.Context) {
 
 	// Prepare response
 	userResponse := models.UserResponse{
-		ID:        user.ID,
-		Name:      user.Name,
-		Email:     user.Email,
-		Role:      user.Role,
-		Location:  user.Location,
-		Bio:       user.Bio,
-		CreatedAt: user.CreatedAt,
+		ID:                  user.ID,
+		Username:            userHandle(user),
+		Name:                user.Name,
+		Email:               user.Email,
+		Role:                user.Role,
+		Location:            user.Location,
+		Bio:                 user.Bio,
+		Verified:            user.Verified,
+		DeletionScheduledAt: user.DeletionScheduledAt,
+		CreatedAt:           user.CreatedAt,
 	}
 
 	if user.Company != nil {
@@ -123,11 +201,40 @@ func LoginUser(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Login successful",
-		"token":   token,
-		"user":    userResponse,
+	response := gin.H{
+		"success":       true,
+		"message":       "Login successful",
+		"token":         token,
+		"refresh_token": refreshToken,
+		"expires_in":    int(utils.AccessTokenTTL.Seconds()),
+		"user":          userResponse,
+	}
+	if recoveryCodes != nil {
+		response["recovery_codes"] = recoveryCodes
+	}
+
+	utils.RecordAuditAs(c, user.ID, "auth.login", "user", user.ID, nil)
+
+	c.JSON(http.StatusOK, response)
+}
+
+func recordLoginFailure(c *gin.Context, email string) {
+	utils.RecordAuditAs(c, 0, "auth.login_failed", "email", email, nil)
+	if err := utils.GetLoginLimiter().RecordFailure(email, c.ClientIP()); err != nil {
+		log.Println("Warning: Failed to record login attempt:", err)
+	}
+}
+
+func tooManyLoginAttempts(c *gin.Context, retryAfter time.Duration) {
+	tooManyRequests(c, retryAfter, "Too many failed login attempts, please try again later")
+}
+
+func tooManyRequests(c *gin.Context, retryAfter time.Duration, message string) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":       message,
+		"retry_after": seconds,
 	})
 }
 
@@ -140,14 +247,33 @@ func GetProfile(c *gin.Context) {
 		return
 	}
 
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"user":    ownProfileResponse(user),
+	})
+}
+
+// ownProfileResponse is the full profile shown to the user themselves
+func ownProfileResponse(user config.User) models.UserResponse {
 	userResponse := models.UserResponse{
-		ID:        user.ID,
-		Name:      user.Name,
-		Email:     user.Email,
-		Role:      user.Role,
-		Location:  user.Location,
-		Bio:       user.Bio,
-		CreatedAt: user.CreatedAt,
+		ID:                  user.ID,
+		Username:            userHandle(user),
+		Name:                user.Name,
+		Email:               user.Email,
+		Role:                user.Role,
+		Location:            user.Location,
+		Bio:                 user.Bio,
+		Skills:              splitSkills(user.Skills),
+		Website:             user.Website,
+		BehanceURL:          user.BehanceURL,
+		DribbbleURL:         user.DribbbleURL,
+		LinkedInURL:         user.LinkedInURL,
+		TwitterURL:          user.TwitterURL,
+		AvatarURL:           user.AvatarURL,
+		ForHire:             user.ForHire,
+		Verified:            user.Verified,
+		DeletionScheduledAt: user.DeletionScheduledAt,
+		CreatedAt:           user.CreatedAt,
 	}
 
 	if user.Company != nil {
@@ -163,40 +289,82 @@ func GetProfile(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"user":    userResponse,
-	})
+	return userResponse
 }
 
+// UpdateProfile - Partially update the current user's profile; fields left
+// out of the body are not touched
 func UpdateProfile(c *gin.Context) {
 	userID, _ := c.Get("user_id")
 
-	var req struct {
-		Name     string `json:"name"`
-		Location string `json:"location"`
-		Bio      string `json:"bio"`
-	}
-
+	var req models.UpdateProfileRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Update user
-	result := config.DB.Model(&config.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
-		"name":     req.Name,
-		"location": req.Location,
-		"bio":      req.Bio,
-	})
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Name cannot be empty"})
+			return
+		}
+		updates["name"] = name
+	}
+	if req.Location != nil {
+		updates["location"] = strings.TrimSpace(*req.Location)
+	}
+	if req.Bio != nil {
+		updates["bio"] = strings.TrimSpace(*req.Bio)
+	}
+	if req.Skills != nil {
+		updates["skills"] = strings.Join(utils.NormalizeSkills(*req.Skills), ",")
+	}
+	if req.ForHire != nil {
+		updates["for_hire"] = *req.ForHire
+	}
 
-	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
+	links := []struct {
+		field  string
+		column string
+		value  *string
+		hosts  []string
+	}{
+		{"website", "website", req.Website, nil},
+		{"behance_url", "behance_url", req.BehanceURL, utils.BehanceHosts},
+		{"dribbble_url", "dribbble_url", req.DribbbleURL, utils.DribbbleHosts},
+		{"linkedin_url", "linked_in_url", req.LinkedInURL, utils.LinkedInHosts},
+		{"twitter_url", "twitter_url", req.TwitterURL, utils.TwitterHosts},
+	}
+	for _, link := range links {
+		if link.value == nil {
+			continue
+		}
+		value, err := utils.ValidateProfileURL(*link.value, link.hosts...)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": link.field + " " + err.Error()})
+			return
+		}
+		updates[link.column] = value
+	}
+
+	if len(updates) > 0 {
+		if err := config.DB.Model(&config.User{}).Where("id = ?", userID).Updates(updates).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
+			return
+		}
+	}
+
+	var user config.User
+	if err := config.DB.Preload("Company").Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Profile updated successfully",
+		"user":    ownProfileResponse(user),
 	})
 }
@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"jobconnect-backend/config"
+	"jobconnect-backend/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// dateLayout is how availability dates are sent and shown
+const dateLayout = "2006-01-02"
+
+func availabilityResponse(window config.AvailabilityWindow) models.AvailabilityResponse {
+	return models.AvailabilityResponse{
+		ID:        window.ID,
+		StartDate: window.StartDate.Format(dateLayout),
+		EndDate:   window.EndDate.Format(dateLayout),
+		Status:    window.Status,
+		Note:      window.Note,
+	}
+}
+
+// upcomingAvailability lists the user's windows that haven't ended yet
+func upcomingAvailability(userID uint) []models.AvailabilityResponse {
+	var windows []config.AvailabilityWindow
+	config.DB.Where("user_id = ? AND end_date >= CURRENT_DATE", userID).Order("start_date").Find(&windows)
+
+	response := []models.AvailabilityResponse{}
+	for _, window := range windows {
+		response = append(response, availabilityResponse(window))
+	}
+	return response
+}
+
+// userRateCards loads the rate cards of the given users, keyed by user. Users
+// without rates get an empty list.
+func userRateCards(userIDs []uint) map[uint][]models.RateCardResponse {
+	rates := make(map[uint][]models.RateCardResponse)
+	if len(userIDs) == 0 {
+		return rates
+	}
+	for _, id := range userIDs {
+		rates[id] = []models.RateCardResponse{}
+	}
+
+	var cards []config.RateCard
+	config.DB.Where("user_id IN ?", userIDs).Order("unit, currency").Find(&cards)
+	for _, card := range cards {
+		rates[card.UserID] = append(rates[card.UserID], models.RateCardResponse{
+			Unit:        card.Unit,
+			Amount:      card.Amount,
+			Currency:    card.Currency,
+			Description: card.Description,
+		})
+	}
+	return rates
+}
+
+// rateCardsOf is userRateCards for a single user
+func rateCardsOf(userID uint) []models.RateCardResponse {
+	return userRateCards([]uint{userID})[userID]
+}
+
+// bindAvailabilityWindow validates a window request for the user. Overlaps
+// are checked by saveAvailabilityWindow.
+func bindAvailabilityWindow(c *gin.Context, userID uint) (config.AvailabilityWindow, bool) {
+	var window config.AvailabilityWindow
+
+	var req models.AvailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return window, false
+	}
+
+	// Both already passed the datetime binding check
+	start, _ := time.Parse(dateLayout, req.StartDate)
+	end, _ := time.Parse(dateLayout, req.EndDate)
+	if end.Before(start) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_date must not be before start_date"})
+		return window, false
+	}
+	if end.Before(time.Now().UTC().Truncate(24 * time.Hour)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_date is in the past"})
+		return window, false
+	}
+
+	window = config.AvailabilityWindow{
+		UserID:    userID,
+		StartDate: start,
+		EndDate:   end,
+		Status:    req.Status,
+		Note:      strings.TrimSpace(req.Note),
+	}
+	return window, true
+}
+
+// windowOverlapError is returned by saveAvailabilityWindow when the window
+// overlaps another of the user's windows
+type windowOverlapError struct {
+	window config.AvailabilityWindow
+}
+
+func (e windowOverlapError) Error() string {
+	return fmt.Sprintf(
+		"Overlaps your %s window from %s to %s",
+		e.window.Status, e.window.StartDate.Format(dateLayout), e.window.EndDate.Format(dateLayout),
+	)
+}
+
+// saveAvailabilityWindow creates the window, or when id is set updates that
+// window of the user, unless it overlaps one of their other windows. The
+// user's row is locked so two requests can't both pass the overlap check.
+func saveAvailabilityWindow(window *config.AvailabilityWindow, id uint) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		var user config.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+			Where("id = ?", window.UserID).First(&user).Error; err != nil {
+			return err
+		}
+
+		var overlapping config.AvailabilityWindow
+		err := tx.Where("user_id = ? AND id <> ? AND start_date <= ? AND end_date >= ?", window.UserID, id, window.EndDate, window.StartDate).
+			First(&overlapping).Error
+		if err == nil {
+			return windowOverlapError{overlapping}
+		}
+		if err != gorm.ErrRecordNotFound {
+			return err
+		}
+
+		if id == 0 {
+			return tx.Create(window).Error
+		}
+		result := tx.Model(&config.AvailabilityWindow{}).Where("id = ? AND user_id = ?", id, window.UserID).Updates(map[string]interface{}{
+			"start_date": window.StartDate,
+			"end_date":   window.EndDate,
+			"status":     window.Status,
+			"note":       window.Note,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		window.ID = id
+		return nil
+	})
+}
+
+// availabilitySaveFailed answers for an error from saveAvailabilityWindow
+func availabilitySaveFailed(c *gin.Context, err error) {
+	var overlap windowOverlapError
+	switch {
+	case errors.As(err, &overlap):
+		c.JSON(http.StatusBadRequest, gin.H{"error": overlap.Error()})
+	case err == gorm.ErrRecordNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Availability window not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save availability"})
+	}
+}
+
+// GetMyAvailability - The current user's availability windows, past ones
+// included
+func GetMyAvailability(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var windows []config.AvailabilityWindow
+	config.DB.Where("user_id = ?", userID).Order("start_date").Find(&windows)
+
+	response := []models.AvailabilityResponse{}
+	for _, window := range windows {
+		response = append(response, availabilityResponse(window))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"availability": response,
+	})
+}
+
+// AddAvailability - Mark a date range as available or booked
+func AddAvailability(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	window, ok := bindAvailabilityWindow(c, userID.(uint))
+	if !ok {
+		return
+	}
+
+	if err := saveAvailabilityWindow(&window, 0); err != nil {
+		availabilitySaveFailed(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":      true,
+		"message":      "Availability saved",
+		"availability": availabilityResponse(window),
+	})
+}
+
+// UpdateAvailability - Change the dates or status of one of the user's windows
+func UpdateAvailability(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var existing config.AvailabilityWindow
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&existing).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Availability window not found"})
+		return
+	}
+
+	window, ok := bindAvailabilityWindow(c, existing.UserID)
+	if !ok {
+		return
+	}
+
+	if err := saveAvailabilityWindow(&window, existing.ID); err != nil {
+		availabilitySaveFailed(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"message":      "Availability updated",
+		"availability": availabilityResponse(window),
+	})
+}
+
+// DeleteAvailability - Remove one of the user's windows
+func DeleteAvailability(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	result := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).Delete(&config.AvailabilityWindow{})
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Availability window not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Availability removed",
+	})
+}
+
+// GetMyRates - The current user's rate cards
+func GetMyRates(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"rates":   rateCardsOf(userID.(uint)),
+	})
+}
+
+// SetRates - Replace the current user's rate cards. There can be one rate per
+// unit and currency.
+func SetRates(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.SetRatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cards := []config.RateCard{}
+	seen := make(map[string]bool)
+	for _, rate := range req.Rates {
+		currency := strings.ToUpper(rate.Currency)
+		key := rate.Unit + " " + currency
+		if seen[key] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Only one " + rate.Unit + " rate per currency"})
+			return
+		}
+		seen[key] = true
+		cards = append(cards, config.RateCard{
+			UserID:      userID.(uint),
+			Unit:        rate.Unit,
+			Amount:      rate.Amount,
+			Currency:    currency,
+			Description: strings.TrimSpace(rate.Description),
+		})
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&config.RateCard{}).Error; err != nil {
+			return err
+		}
+		if len(cards) == 0 {
+			return nil
+		}
+		return tx.Create(&cards).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save rates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Rates updated",
+		"rates":   rateCardsOf(userID.(uint)),
+	})
+}
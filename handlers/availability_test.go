@@ -0,0 +1,55 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"jobconnect-backend/config"
+)
+
+func TestUpdateAvailabilityChecksOverlaps(t *testing.T) {
+	r, _ := setupServer(t)
+
+	_, token := creative(t, r, "ada@example.com")
+	day := func(n int) string {
+		return time.Now().UTC().AddDate(0, 0, n).Format("2006-01-02")
+	}
+	add := func(start, end int) uint {
+		status, body := doJSON(t, r, http.MethodPost, "/api/profile/availability", token, map[string]string{
+			"start_date": day(start), "end_date": day(end), "status": "booked",
+		})
+		if status != http.StatusCreated {
+			t.Fatalf("add window: got %d %v", status, body)
+		}
+		return uint(body["availability"].(map[string]interface{})["id"].(float64))
+	}
+	add(1, 5)
+	second := add(10, 12)
+	path := fmt.Sprintf("/api/profile/availability/%d", second)
+
+	status, _ := doJSON(t, r, http.MethodPut, path, token, map[string]string{
+		"start_date": day(4), "end_date": day(12), "status": "booked",
+	})
+	if status != http.StatusBadRequest {
+		t.Fatalf("moving onto another window: got %d, want 400", status)
+	}
+	if status, _ := doJSON(t, r, http.MethodPost, "/api/profile/availability", token, map[string]string{
+		"start_date": day(11), "end_date": day(14), "status": "available",
+	}); status != http.StatusBadRequest {
+		t.Fatalf("adding an overlapping window: got %d, want 400", status)
+	}
+
+	status, body := doJSON(t, r, http.MethodPut, path, token, map[string]string{
+		"start_date": day(6), "end_date": day(12), "status": "available",
+	})
+	if status != http.StatusOK {
+		t.Fatalf("update: got %d %v", status, body)
+	}
+	var window config.AvailabilityWindow
+	config.DB.First(&window, second)
+	if window.StartDate.Format("2006-01-02") != day(6) || window.Status != "available" {
+		t.Fatalf("window not updated: %+v", window)
+	}
+}
@@ -0,0 +1,507 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"jobconnect-backend/config"
+	"jobconnect-backend/models"
+	"jobconnect-backend/utils"
+
+	"github.com/cloudinary/cloudinary-go/v2"
+	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// companyInvitationTTL is how long an invitation link can be accepted
+const companyInvitationTTL = 7 * 24 * time.Hour
+
+func companyResponse(company config.Company) models.CompanyResponse {
+	return models.CompanyResponse{
+		ID:          company.ID,
+		Name:        company.Name,
+		Description: company.Description,
+		Website:     company.Website,
+		Location:    company.Location,
+		LogoURL:     company.LogoURL,
+		Industry:    company.Industry,
+		Size:        company.Size,
+		OwnerID:     company.OwnerID,
+	}
+}
+
+// currentCompanyUser loads the authenticated user and makes sure they belong
+// to a company, answering 404 otherwise
+func currentCompanyUser(c *gin.Context) (config.User, bool) {
+	userID, _ := c.Get("user_id")
+
+	var user config.User
+	if err := config.DB.Preload("Company").Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return user, false
+	}
+	if user.CompanyID == nil || user.Company == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No company profile found"})
+		return user, false
+	}
+	return user, true
+}
+
+// CreateCompany - Company user creates their company profile
+func CreateCompany(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.CreateCompanyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	website, err := utils.ValidateProfileURL(req.Website)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "website " + err.Error()})
+		return
+	}
+
+	var user config.User
+	if err := config.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if user.CompanyID != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You already have a company profile"})
+		return
+	}
+
+	company := config.Company{
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		Website:     website,
+		Location:    req.Location,
+		Industry:    req.Industry,
+		Size:        req.Size,
+		OwnerID:     &user.ID,
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&company).Error; err != nil {
+			return err
+		}
+		return tx.Model(&user).Update("company_id", company.ID).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create company"})
+		return
+	}
+
+	utils.RecordAudit(c, "company.create", "company", company.ID, utils.AuditDiff{
+		"name": {From: nil, To: company.Name},
+	})
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":    true,
+		"message":    "Company created successfully",
+		"company_id": company.ID,
+	})
+}
+
+// GetMyCompany - Company user gets their company
+func GetMyCompany(c *gin.Context) {
+	user, ok := currentCompanyUser(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"company": companyResponse(*user.Company),
+	})
+}
+
+// UpdateCompany - Company member partially updates their company; fields
+// left out of the body are not touched
+func UpdateCompany(c *gin.Context) {
+	var req models.UpdateCompanyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, ok := currentCompanyUser(c)
+	if !ok {
+		return
+	}
+	previous := *user.Company
+
+	fields := []struct {
+		column   string
+		value    *string
+		previous string
+	}{
+		{"name", req.Name, previous.Name},
+		{"description", req.Description, previous.Description},
+		{"website", req.Website, previous.Website},
+		{"location", req.Location, previous.Location},
+		{"industry", req.Industry, previous.Industry},
+		{"size", req.Size, previous.Size},
+	}
+
+	updates := make(map[string]interface{})
+	diff := utils.AuditDiff{}
+	for _, field := range fields {
+		if field.value == nil {
+			continue
+		}
+		value := strings.TrimSpace(*field.value)
+		switch field.column {
+		case "name":
+			if value == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Name cannot be empty"})
+				return
+			}
+		case "website":
+			website, err := utils.ValidateProfileURL(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "website " + err.Error()})
+				return
+			}
+			value = website
+		}
+		if value != field.previous {
+			updates[field.column] = value
+			diff[field.column] = utils.AuditChange{From: field.previous, To: value}
+		}
+	}
+
+	if len(updates) > 0 {
+		if err := config.DB.Model(&config.Company{}).Where("id = ?", previous.ID).Updates(updates).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update company"})
+			return
+		}
+		utils.RecordAudit(c, "company.update", "company", previous.ID, diff)
+	}
+
+	var company config.Company
+	if err := config.DB.Where("id = ?", previous.ID).First(&company).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Company updated successfully",
+		"company": companyResponse(company),
+	})
+}
+
+// UploadCompanyLogo - Upload company logo to Cloudinary
+func UploadCompanyLogo(c *gin.Context) {
+	user, ok := currentCompanyUser(c)
+	if !ok {
+		return
+	}
+
+	// Get uploaded file
+	file, err := c.FormFile("logo")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No file uploaded"})
+		return
+	}
+
+	// Open file
+	fileContent, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open file"})
+		return
+	}
+	defer fileContent.Close()
+
+	// Setup Cloudinary
+	cld, err := cloudinary.NewFromParams(
+		os.Getenv("CLOUDINARY_CLOUD_NAME"),
+		os.Getenv("CLOUDINARY_API_KEY"),
+		os.Getenv("CLOUDINARY_API_SECRET"),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to setup Cloudinary"})
+		return
+	}
+
+	// Upload to Cloudinary
+	ctx := context.Background()
+	uploadResult, err := cld.Upload.Upload(ctx, fileContent, uploader.UploadParams{
+		Folder: "rosyartgrid/logos",
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload logo"})
+		return
+	}
+
+	// Update company logo URL
+	recordUpload(user.ID, uploadResult.PublicID, uploadResult.SecureURL)
+	config.DB.Model(&config.Company{}).Where("id = ?", user.Company.ID).Update("logo_url", uploadResult.SecureURL)
+
+	utils.RecordAudit(c, "upload.company_logo", "company", user.Company.ID, utils.AuditDiff{
+		"logo_url": {From: user.Company.LogoURL, To: uploadResult.SecureURL},
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"message":  "Logo uploaded successfully",
+		"logo_url": uploadResult.SecureURL,
+	})
+}
+
+// GetCompanyPage - Public company page with its team members
+func GetCompanyPage(c *gin.Context) {
+	var company config.Company
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&company).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		return
+	}
+
+	var members []config.User
+	config.DB.Where("company_id = ? AND status NOT IN ?", company.ID, []string{utils.StatusBanned, utils.StatusDeleted}).
+		Order("created_at").Find(&members)
+
+	response := companyResponse(company)
+	response.Members = []models.UserResponse{}
+	for _, member := range members {
+		response.Members = append(response.Members, models.UserResponse{
+			ID:        member.ID,
+			Username:  userHandle(member),
+			Name:      member.Name,
+			Role:      member.Role,
+			Location:  member.Location,
+			AvatarURL: member.AvatarURL,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"company": response,
+	})
+}
+
+// InviteRecruiter - Company member invites another recruiter by email
+func InviteRecruiter(c *gin.Context) {
+	var req models.InviteRecruiterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, ok := currentCompanyUser(c)
+	if !ok {
+		return
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	var count int64
+	config.DB.Model(&config.User{}).Where("LOWER(email) = ? AND company_id = ?", email, user.Company.ID).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This user is already a member of your company"})
+		return
+	}
+
+	token, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	invitation := config.CompanyInvitation{
+		CompanyID:   user.Company.ID,
+		Email:       email,
+		TokenHash:   utils.HashToken(token),
+		InvitedByID: user.ID,
+		ExpiresAt:   time.Now().Add(companyInvitationTTL),
+	}
+	if err := config.DB.Create(&invitation).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create invitation"})
+		return
+	}
+
+	body := fmt.Sprintf(
+		"Hi,\n\n%s invited you to join %s on RosyArtGrid. Sign in or register a company account with this email address, then open:\n\n%s\n\nThis link expires in %d days.",
+		user.Name, user.Company.Name, utils.FrontendLink("/company/join", token), int(companyInvitationTTL.Hours()/24),
+	)
+	if err := utils.GetMailer().Send(email, "Join "+user.Company.Name+" on RosyArtGrid", body); err != nil {
+		log.Println("Warning: Failed to send company invitation:", err)
+	}
+
+	utils.RecordAudit(c, "company.invite", "company", user.Company.ID, utils.AuditDiff{
+		"email": {From: nil, To: email},
+	})
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":       true,
+		"message":       "Invitation sent",
+		"invitation_id": invitation.ID,
+	})
+}
+
+// GetCompanyInvitations - Company member lists pending invitations
+func GetCompanyInvitations(c *gin.Context) {
+	user, ok := currentCompanyUser(c)
+	if !ok {
+		return
+	}
+
+	var invitations []config.CompanyInvitation
+	config.DB.Preload("InvitedBy").
+		Where("company_id = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", user.Company.ID, time.Now()).
+		Order("created_at DESC").Find(&invitations)
+
+	response := []models.CompanyInvitationResponse{}
+	for _, invitation := range invitations {
+		response = append(response, models.CompanyInvitationResponse{
+			ID:    invitation.ID,
+			Email: invitation.Email,
+			InvitedBy: models.UserResponse{
+				ID:        invitation.InvitedBy.ID,
+				Name:      invitation.InvitedBy.Name,
+				AvatarURL: invitation.InvitedBy.AvatarURL,
+			},
+			ExpiresAt: invitation.ExpiresAt,
+			CreatedAt: invitation.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"invitations": response,
+	})
+}
+
+// RevokeCompanyInvitation - Company member cancels a pending invitation
+func RevokeCompanyInvitation(c *gin.Context) {
+	user, ok := currentCompanyUser(c)
+	if !ok {
+		return
+	}
+
+	result := config.DB.Model(&config.CompanyInvitation{}).
+		Where("id = ? AND company_id = ? AND accepted_at IS NULL AND revoked_at IS NULL", c.Param("id"), user.Company.ID).
+		Update("revoked_at", time.Now())
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invitation not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Invitation revoked",
+	})
+}
+
+// AcceptCompanyInvitation - Recruiter joins the company that invited them
+func AcceptCompanyInvitation(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var invitation config.CompanyInvitation
+	if err := config.DB.Preload("Company").
+		Where("token_hash = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", utils.HashToken(req.Token), time.Now()).
+		First(&invitation).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired invitation"})
+		return
+	}
+
+	var user config.User
+	if err := config.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "This invitation was sent to a different email address"})
+		return
+	}
+	if user.CompanyID != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You already belong to a company"})
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		// Guard against the same link being used twice concurrently
+		result := tx.Model(&config.CompanyInvitation{}).
+			Where("id = ? AND accepted_at IS NULL", invitation.ID).
+			Updates(map[string]interface{}{"accepted_at": time.Now(), "accepted_by_id": user.ID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Model(&user).Update("company_id", invitation.CompanyID).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired invitation"})
+		return
+	}
+
+	utils.RecordAudit(c, "company.join", "company", invitation.CompanyID, utils.AuditDiff{
+		"member_id": {From: nil, To: user.ID},
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "You joined " + invitation.Company.Name,
+		"company": companyResponse(invitation.Company),
+	})
+}
+
+// RemoveCompanyMember - Company owner removes a teammate; other members can
+// only remove themselves, to leave
+func RemoveCompanyMember(c *gin.Context) {
+	user, ok := currentCompanyUser(c)
+	if !ok {
+		return
+	}
+
+	var member config.User
+	if err := config.DB.Where("id = ? AND company_id = ?", c.Param("id"), user.Company.ID).First(&member).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+		return
+	}
+
+	isOwner := func(id uint) bool {
+		return user.Company.OwnerID != nil && *user.Company.OwnerID == id
+	}
+	if isOwner(member.ID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The company owner can't be removed"})
+		return
+	}
+	if member.ID != user.ID && !isOwner(user.ID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the company owner can remove members"})
+		return
+	}
+
+	if err := config.DB.Model(&member).Update("company_id", nil).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove member"})
+		return
+	}
+
+	utils.RecordAudit(c, "company.member_remove", "company", user.Company.ID, utils.AuditDiff{
+		"member_id": {From: member.ID, To: nil},
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Member removed",
+	})
+}
@@ -0,0 +1,170 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"jobconnect-backend/config"
+	"jobconnect-backend/utils"
+)
+
+// recruiter registers a verified company account and returns its ID and token
+func recruiter(t *testing.T, r http.Handler, email string) (uint, string) {
+	t.Helper()
+
+	userID := register(t, r, email)
+	config.DB.Model(&config.User{}).Where("id = ?", userID).Updates(map[string]interface{}{"role": "company", "verified": true})
+	return userID, login(t, r, email)
+}
+
+// joinCompany invites email to the owner's company and accepts as that user
+func joinCompany(t *testing.T, r http.Handler, mailer *utils.MemoryMailer, ownerToken, email, token string) {
+	t.Helper()
+
+	status, body := doJSON(t, r, http.MethodPost, "/api/company/invitations", ownerToken, map[string]string{"email": email})
+	if status != http.StatusCreated {
+		t.Fatalf("invite %s: got %d %v", email, status, body)
+	}
+	status, body = doJSON(t, r, http.MethodPost, "/api/company/invitations/accept", token, map[string]string{"token": mailedToken(t, mailer, email)})
+	if status != http.StatusOK {
+		t.Fatalf("accept as %s: got %d %v", email, status, body)
+	}
+}
+
+func TestOnlyCompanyOwnerRemovesMembers(t *testing.T) {
+	r, mailer := setupServer(t)
+
+	ownerID, ownerToken := recruiter(t, r, "owner@example.com")
+	status, body := doJSON(t, r, http.MethodPost, "/api/company", ownerToken, map[string]string{"name": "Acme"})
+	if status != http.StatusCreated {
+		t.Fatalf("create company: got %d %v", status, body)
+	}
+
+	bobID, bobToken := recruiter(t, r, "bob@example.com")
+	carolID, carolToken := recruiter(t, r, "carol@example.com")
+	joinCompany(t, r, mailer, ownerToken, "bob@example.com", bobToken)
+	joinCompany(t, r, mailer, ownerToken, "carol@example.com", carolToken)
+
+	remove := func(token string, memberID uint) int {
+		status, _ := doJSON(t, r, http.MethodDelete, fmt.Sprintf("/api/company/members/%d", memberID), token, nil)
+		return status
+	}
+	if status := remove(bobToken, carolID); status != http.StatusForbidden {
+		t.Fatalf("member removing a teammate: got %d, want 403", status)
+	}
+	if status := remove(bobToken, ownerID); status != http.StatusBadRequest {
+		t.Fatalf("member removing the owner: got %d, want 400", status)
+	}
+	if status := remove(ownerToken, ownerID); status != http.StatusBadRequest {
+		t.Fatalf("owner leaving: got %d, want 400", status)
+	}
+	if status := remove(ownerToken, carolID); status != http.StatusOK {
+		t.Fatalf("owner removing a member: got %d, want 200", status)
+	}
+	if status := remove(bobToken, bobID); status != http.StatusOK {
+		t.Fatalf("member leaving: got %d, want 200", status)
+	}
+
+	var members int64
+	config.DB.Model(&config.User{}).Where("company_id IS NOT NULL").Count(&members)
+	if members != 1 {
+		t.Fatalf("expected only the owner left, got %d members", members)
+	}
+}
+
+func TestUpdateCompanyIsPartialAndAudited(t *testing.T) {
+	r, _ := setupServer(t)
+
+	_, token := recruiter(t, r, "owner@example.com")
+	status, body := doJSON(t, r, http.MethodPost, "/api/company", token, map[string]string{
+		"name":     "Acme",
+		"location": "Lisbon",
+		"website":  "https://acme.example",
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("create company: got %d %v", status, body)
+	}
+	companyID := uint(body["company_id"].(float64))
+
+	status, body = doJSON(t, r, http.MethodPatch, "/api/company", token, map[string]string{"location": "Porto", "industry": "Games"})
+	if status != http.StatusOK {
+		t.Fatalf("update company: got %d %v", status, body)
+	}
+
+	var company config.Company
+	config.DB.First(&company, companyID)
+	if company.Name != "Acme" || company.Website != "https://acme.example" {
+		t.Fatalf("fields left out were changed: %+v", company)
+	}
+	if company.Location != "Porto" || company.Industry != "Games" {
+		t.Fatalf("fields sent weren't updated: %+v", company)
+	}
+
+	var event config.AuditEvent
+	if err := config.DB.Where("action = ?", "company.update").First(&event).Error; err != nil {
+		t.Fatal("update not audited:", err)
+	}
+	for _, field := range []string{"location", "industry"} {
+		if !strings.Contains(event.Changes, `"`+field+`"`) {
+			t.Errorf("audit diff is missing %s: %s", field, event.Changes)
+		}
+	}
+	if strings.Contains(event.Changes, `"name"`) {
+		t.Errorf("audit diff has an unchanged field: %s", event.Changes)
+	}
+
+	status, _ = doJSON(t, r, http.MethodPatch, "/api/company", token, map[string]string{"name": "  "})
+	if status != http.StatusBadRequest {
+		t.Fatalf("blank name: got %d, want 400", status)
+	}
+}
+
+func TestCreativesCantJoinCompanies(t *testing.T) {
+	r, mailer := setupServer(t)
+
+	_, ownerToken := recruiter(t, r, "owner@example.com")
+	doJSON(t, r, http.MethodPost, "/api/company", ownerToken, map[string]string{"name": "Acme"})
+	status, body := doJSON(t, r, http.MethodPost, "/api/company/invitations", ownerToken, map[string]string{"email": "ada@example.com"})
+	if status != http.StatusCreated {
+		t.Fatalf("invite: got %d %v", status, body)
+	}
+
+	adaID, adaToken := creative(t, r, "ada@example.com")
+	status, _ = doJSON(t, r, http.MethodPost, "/api/company/invitations/accept", adaToken, map[string]string{"token": mailedToken(t, mailer, "ada@example.com")})
+	if status != http.StatusForbidden {
+		t.Fatalf("creative accepting: got %d, want 403", status)
+	}
+	status, _ = doJSON(t, r, http.MethodGet, "/api/company", adaToken, nil)
+	if status != http.StatusForbidden {
+		t.Fatalf("creative reading company: got %d, want 403", status)
+	}
+
+	var user config.User
+	config.DB.First(&user, adaID)
+	if user.CompanyID != nil {
+		t.Fatal("creative joined the company")
+	}
+}
+
+func TestCompanyManagePermissionIsEnoughToJoin(t *testing.T) {
+	r, mailer := setupServer(t)
+
+	// A custom role an admin granted company.manage to
+	config.DB.Create(&config.RolePermission{Role: "agency", Permission: utils.PermCompanyManage})
+	utils.InvalidatePermissionCache()
+	t.Cleanup(utils.InvalidatePermissionCache)
+	agent := func(email string) string {
+		userID := register(t, r, email)
+		config.DB.Model(&config.User{}).Where("id = ?", userID).Updates(map[string]interface{}{"role": "agency", "verified": true})
+		return login(t, r, email)
+	}
+
+	ownerToken := agent("owner@example.com")
+	status, body := doJSON(t, r, http.MethodPost, "/api/company", ownerToken, map[string]string{"name": "Acme"})
+	if status != http.StatusCreated {
+		t.Fatalf("create company: got %d %v", status, body)
+	}
+	joinCompany(t, r, mailer, ownerToken, "bob@example.com", agent("bob@example.com"))
+}
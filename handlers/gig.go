@@ -0,0 +1,660 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"jobconnect-backend/config"
+	"jobconnect-backend/models"
+	"jobconnect-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Gig and application statuses
+const (
+	gigOpen   = "open"
+	gigClosed = "closed"
+
+	applicationSubmitted   = "submitted"
+	applicationShortlisted = "shortlisted"
+	applicationAccepted    = "accepted"
+	applicationRejected    = "rejected"
+)
+
+// applicationTransitions lists the statuses a company can move an application
+// to from its current one. Accepted and rejected are final.
+var applicationTransitions = map[string][]string{
+	applicationSubmitted:   {applicationShortlisted, applicationAccepted, applicationRejected},
+	applicationShortlisted: {applicationSubmitted, applicationAccepted, applicationRejected},
+}
+
+func canMoveApplication(from, to string) bool {
+	for _, status := range applicationTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+func gigResponse(gig config.Gig, applicationsCount int64) models.GigResponse {
+	return models.GigResponse{
+		ID:       gig.ID,
+		Company:  companyResponse(gig.Company),
+		PostedBy: userSummary(gig.PostedBy),
+		Category: models.CategoryResponse{
+			ID:   gig.Category.ID,
+			Name: gig.Category.Name,
+			Slug: gig.Category.Slug,
+			Icon: gig.Category.Icon,
+		},
+		Title:             gig.Title,
+		Description:       gig.Description,
+		BudgetMin:         gig.BudgetMin,
+		BudgetMax:         gig.BudgetMax,
+		Currency:          gig.Currency,
+		Deadline:          gig.Deadline,
+		Status:            gig.Status,
+		ApplicationsCount: applicationsCount,
+		CreatedAt:         gig.CreatedAt,
+		UpdatedAt:         gig.UpdatedAt,
+	}
+}
+
+// gigApplicationCounts counts the applications of each of the given gigs
+func gigApplicationCounts(gigIDs []uint) map[uint]int64 {
+	counts := make(map[uint]int64)
+	if len(gigIDs) == 0 {
+		return counts
+	}
+
+	var rows []struct {
+		GigID uint
+		Count int64
+	}
+	config.DB.Model(&config.GigApplication{}).
+		Select("gig_id, COUNT(*) AS count").
+		Where("gig_id IN ?", gigIDs).
+		Group("gig_id").
+		Scan(&rows)
+	for _, row := range rows {
+		counts[row.GigID] = row.Count
+	}
+	return counts
+}
+
+// preloadGigApplication loads what an application response needs: the
+// applicant and the attached projects in the order they were picked
+func preloadGigApplication(db *gorm.DB) *gorm.DB {
+	return db.Preload("Applicant").
+		Preload("Projects", func(db *gorm.DB) *gorm.DB {
+			return db.Order(`gig_application_projects."order"`)
+		}).
+		Preload("Projects.Project.Category")
+}
+
+func gigApplicationResponse(application config.GigApplication) models.GigApplicationResponse {
+	applicant := userSummary(application.Applicant)
+	applicant.Location = application.Applicant.Location
+	applicant.ForHire = application.Applicant.ForHire
+
+	projects := []models.ProjectResponse{}
+	for _, link := range application.Projects {
+		// Work taken down by moderators or unpublished since is no longer shown
+		if link.Project.DeletedAt != nil || link.Project.Status != projectPublished {
+			continue
+		}
+		projects = append(projects, models.ProjectResponse{
+			ID:          link.Project.ID,
+			Title:       link.Project.Title,
+			Description: link.Project.Description,
+			CoverImage:  link.Project.CoverImage,
+			Category: models.CategoryResponse{
+				ID:   link.Project.Category.ID,
+				Name: link.Project.Category.Name,
+				Slug: link.Project.Category.Slug,
+				Icon: link.Project.Category.Icon,
+			},
+			Tags:       link.Project.Tags,
+			Views:      link.Project.Views,
+			LikesCount: link.Project.LikesCount,
+			CreatedAt:  link.Project.CreatedAt,
+		})
+	}
+
+	return models.GigApplicationResponse{
+		ID:        application.ID,
+		Applicant: applicant,
+		CoverNote: application.CoverNote,
+		Status:    application.Status,
+		Projects:  projects,
+		CreatedAt: application.CreatedAt,
+		UpdatedAt: application.UpdatedAt,
+	}
+}
+
+// GetGigs - Browse open gigs (public)
+//
+// Filters: category (slug), q (title/description), min_budget (gigs paying
+// at least this much at the top of their range).
+func GetGigs(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	query := config.DB.Model(&config.Gig{}).
+		Where("status = ? AND deadline > ?", gigOpen, time.Now())
+
+	if categorySlug := c.Query("category"); categorySlug != "" {
+		query = query.Where("category_id IN (SELECT id FROM categories WHERE slug = ?)", categorySlug)
+	}
+
+	if q := c.Query("q"); q != "" {
+		query = query.Where("title ILIKE ? OR description ILIKE ?", "%"+q+"%", "%"+q+"%")
+	}
+
+	if minBudget, err := strconv.Atoi(c.Query("min_budget")); err == nil && minBudget > 0 {
+		query = query.Where("budget_max >= ?", minBudget)
+	}
+
+	var totalCount int64
+	query.Count(&totalCount)
+
+	var gigs []config.Gig
+	query.Preload("Company").Preload("PostedBy").Preload("Category").
+		Order("created_at DESC").Offset(offset).Limit(limit).Find(&gigs)
+
+	ids := make([]uint, len(gigs))
+	for i, gig := range gigs {
+		ids[i] = gig.ID
+	}
+	counts := gigApplicationCounts(ids)
+
+	response := []models.GigResponse{}
+	for _, gig := range gigs {
+		response = append(response, gigResponse(gig, counts[gig.ID]))
+	}
+
+	totalPages := int((totalCount + int64(limit) - 1) / int64(limit))
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"gigs":       response,
+		"page":       page,
+		"totalPages": totalPages,
+		"totalCount": totalCount,
+	})
+}
+
+// GetGig - Get a single gig (public)
+func GetGig(c *gin.Context) {
+	var gig config.Gig
+	if err := config.DB.Preload("Company").Preload("PostedBy").Preload("Category").
+		Where("id = ?", c.Param("id")).First(&gig).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Gig not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"gig":     gigResponse(gig, gigApplicationCounts([]uint{gig.ID})[gig.ID]),
+	})
+}
+
+// companyGig loads a gig posted by the current user's company, answering 404
+// if it belongs to another company
+func companyGig(c *gin.Context) (config.User, config.Gig, bool) {
+	var gig config.Gig
+
+	user, ok := currentCompanyUser(c)
+	if !ok {
+		return user, gig, false
+	}
+
+	if err := config.DB.Where("id = ? AND company_id = ?", c.Param("id"), *user.CompanyID).First(&gig).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Gig not found"})
+		return user, gig, false
+	}
+	return user, gig, true
+}
+
+// validGigRequest checks what binding tags can't: the category exists and an
+// open gig's deadline is still ahead
+func validGigRequest(c *gin.Context, req models.GigRequest) bool {
+	var count int64
+	config.DB.Model(&config.Category{}).Where("id = ?", req.CategoryID).Count(&count)
+	if count == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Category not found"})
+		return false
+	}
+	if req.Status != gigClosed && !req.Deadline.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Deadline must be in the future"})
+		return false
+	}
+	return true
+}
+
+// CreateGig - Company user posts a gig
+func CreateGig(c *gin.Context) {
+	var req models.GigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, ok := currentCompanyUser(c)
+	if !ok {
+		return
+	}
+	if !validGigRequest(c, req) {
+		return
+	}
+
+	status := req.Status
+	if status == "" {
+		status = gigOpen
+	}
+
+	gig := config.Gig{
+		CompanyID:   *user.CompanyID,
+		PostedByID:  user.ID,
+		CategoryID:  req.CategoryID,
+		Title:       strings.TrimSpace(req.Title),
+		Description: strings.TrimSpace(req.Description),
+		BudgetMin:   req.BudgetMin,
+		BudgetMax:   req.BudgetMax,
+		Currency:    strings.ToUpper(req.Currency),
+		Deadline:    req.Deadline,
+		Status:      status,
+	}
+	if err := config.DB.Create(&gig).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create gig"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Gig posted",
+		"gig_id":  gig.ID,
+	})
+}
+
+// GetCompanyGigs - The company's gigs in any status, filter with ?status=
+func GetCompanyGigs(c *gin.Context) {
+	user, ok := currentCompanyUser(c)
+	if !ok {
+		return
+	}
+
+	query := config.DB.Preload("Company").Preload("PostedBy").Preload("Category").
+		Where("company_id = ?", *user.CompanyID)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var gigs []config.Gig
+	query.Order("created_at DESC").Find(&gigs)
+
+	ids := make([]uint, len(gigs))
+	for i, gig := range gigs {
+		ids[i] = gig.ID
+	}
+	counts := gigApplicationCounts(ids)
+
+	response := []models.GigResponse{}
+	for _, gig := range gigs {
+		response = append(response, gigResponse(gig, counts[gig.ID]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"gigs":    response,
+	})
+}
+
+// UpdateGig - Company member edits a gig, or closes it with status "closed"
+func UpdateGig(c *gin.Context) {
+	var req models.GigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	_, gig, ok := companyGig(c)
+	if !ok {
+		return
+	}
+	if req.Status == "" {
+		req.Status = gig.Status
+	}
+	if !validGigRequest(c, req) {
+		return
+	}
+
+	if err := config.DB.Model(&gig).Updates(map[string]interface{}{
+		"category_id": req.CategoryID,
+		"title":       strings.TrimSpace(req.Title),
+		"description": strings.TrimSpace(req.Description),
+		"budget_min":  req.BudgetMin,
+		"budget_max":  req.BudgetMax,
+		"currency":    strings.ToUpper(req.Currency),
+		"deadline":    req.Deadline,
+		"status":      req.Status,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update gig"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Gig updated",
+	})
+}
+
+// DeleteGig - Delete a gig together with its applications
+func DeleteGig(c *gin.Context) {
+	_, gig, ok := companyGig(c)
+	if !ok {
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("application_id IN (SELECT id FROM gig_applications WHERE gig_id = ?)", gig.ID).
+			Delete(&config.GigApplicationProject{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("gig_id = ?", gig.ID).Delete(&config.GigApplication{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&gig).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete gig"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Gig deleted",
+	})
+}
+
+// GetGigApplications - Company member reviews the applications to a gig,
+// filter with ?status=
+func GetGigApplications(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	_, gig, ok := companyGig(c)
+	if !ok {
+		return
+	}
+
+	query := config.DB.Model(&config.GigApplication{}).Where("gig_id = ?", gig.ID)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var totalCount int64
+	query.Count(&totalCount)
+
+	var applications []config.GigApplication
+	preloadGigApplication(query).Order("created_at").Offset(offset).Limit(limit).Find(&applications)
+
+	response := []models.GigApplicationResponse{}
+	for _, application := range applications {
+		response = append(response, gigApplicationResponse(application))
+	}
+
+	totalPages := int((totalCount + int64(limit) - 1) / int64(limit))
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"applications": response,
+		"page":         page,
+		"totalPages":   totalPages,
+		"totalCount":   totalCount,
+	})
+}
+
+// UpdateGigApplication - Company member shortlists, accepts or rejects an
+// application. The applicant is told by email. Accepted and rejected
+// applications are final.
+func UpdateGigApplication(c *gin.Context) {
+	var req models.GigApplicationStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, gig, ok := companyGig(c)
+	if !ok {
+		return
+	}
+
+	var application config.GigApplication
+	if err := config.DB.Preload("Applicant").
+		Where("id = ? AND gig_id = ?", c.Param("applicationId"), gig.ID).First(&application).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+
+	if application.Status == req.Status {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"message": "Application status unchanged",
+			"status":  req.Status,
+		})
+		return
+	}
+
+	if !canMoveApplication(application.Status, req.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Can't move an application from " + application.Status + " to " + req.Status})
+		return
+	}
+
+	// Only move it from the status we checked, in case someone else got there first
+	result := config.DB.Model(&config.GigApplication{}).
+		Where("id = ? AND status = ?", application.ID, application.Status).
+		Update("status", req.Status)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update application"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "The application was changed by someone else, reload and try again"})
+		return
+	}
+
+	if req.Status != applicationSubmitted {
+		body := fmt.Sprintf(
+			"Hi %s,\n\nYour application to \"%s\" at %s is now %s.\n\nSee your gig applications on RosyArtGrid for details.",
+			application.Applicant.Name, gig.Title, user.Company.Name, req.Status,
+		)
+		if err := utils.GetMailer().Send(application.Applicant.Email, "Update on your application to \""+gig.Title+"\"", body); err != nil {
+			log.Println("Warning: Failed to send gig application email:", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Application " + req.Status,
+		"status":  req.Status,
+	})
+}
+
+// ApplyToGig - Creative applies to an open gig, attaching projects from their
+// own portfolio as work samples
+func ApplyToGig(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.ApplyGigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user config.User
+	if err := config.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	var gig config.Gig
+	if err := config.DB.Preload("PostedBy").Where("id = ?", c.Param("id")).First(&gig).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Gig not found"})
+		return
+	}
+	// Admins hold every permission, so check the role itself, and keep
+	// members of the posting company off their own gigs
+	if user.Role != "creative" || (user.CompanyID != nil && *user.CompanyID == gig.CompanyID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only creatives outside the company can apply to this gig"})
+		return
+	}
+	if gig.Status != gigOpen || !gig.Deadline.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This gig is no longer taking applications"})
+		return
+	}
+
+	var count int64
+	config.DB.Model(&config.GigApplication{}).Where("gig_id = ? AND applicant_id = ?", gig.ID, user.ID).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You have already applied to this gig"})
+		return
+	}
+
+	// Keep the order the projects were picked in, without duplicates
+	var projectIDs []uint
+	seen := make(map[uint]bool)
+	for _, id := range req.ProjectIDs {
+		if !seen[id] {
+			seen[id] = true
+			projectIDs = append(projectIDs, id)
+		}
+	}
+	config.DB.Model(&config.Project{}).
+		Where("id IN ? AND user_id = ? AND deleted_at IS NULL AND status = ?", projectIDs, user.ID, projectPublished).
+		Count(&count)
+	if count != int64(len(projectIDs)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You can only attach published projects from your own portfolio"})
+		return
+	}
+
+	application := config.GigApplication{
+		GigID:       gig.ID,
+		ApplicantID: user.ID,
+		CoverNote:   strings.TrimSpace(req.CoverNote),
+		Status:      applicationSubmitted,
+	}
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&application).Error; err != nil {
+			return err
+		}
+		links := make([]config.GigApplicationProject, len(projectIDs))
+		for i, projectID := range projectIDs {
+			links[i] = config.GigApplicationProject{ApplicationID: application.ID, ProjectID: projectID, Order: i}
+		}
+		return tx.Create(&links).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit application"})
+		return
+	}
+
+	body := fmt.Sprintf(
+		"Hi %s,\n\n%s applied to your gig \"%s\" with %d portfolio project(s).\n\nReview applications on RosyArtGrid.",
+		gig.PostedBy.Name, user.Name, gig.Title, len(projectIDs),
+	)
+	if err := utils.GetMailer().Send(gig.PostedBy.Email, "New application to \""+gig.Title+"\"", body); err != nil {
+		log.Println("Warning: Failed to send gig application email:", err)
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":        true,
+		"message":        "Application submitted",
+		"application_id": application.ID,
+	})
+}
+
+// WithdrawGigApplication - Creative withdraws their application, unless it
+// has already been accepted
+func WithdrawGigApplication(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var application config.GigApplication
+	if err := config.DB.Where("gig_id = ? AND applicant_id = ?", c.Param("id"), userID).First(&application).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+	if application.Status == applicationAccepted {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "An accepted application can't be withdrawn"})
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("application_id = ?", application.ID).Delete(&config.GigApplicationProject{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&application).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to withdraw application"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Application withdrawn",
+	})
+}
+
+// GetMyGigApplications - Creative gets their applications with the gigs they
+// were made to
+func GetMyGigApplications(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var applications []config.GigApplication
+	preloadGigApplication(config.DB).
+		Preload("Gig.Company").Preload("Gig.PostedBy").Preload("Gig.Category").
+		Where("applicant_id = ?", userID).
+		Order("updated_at DESC").
+		Find(&applications)
+
+	ids := make([]uint, len(applications))
+	for i, application := range applications {
+		ids[i] = application.GigID
+	}
+	counts := gigApplicationCounts(ids)
+
+	response := []models.GigApplicationResponse{}
+	for _, application := range applications {
+		gig := gigResponse(application.Gig, counts[application.GigID])
+		item := gigApplicationResponse(application)
+		item.Gig = &gig
+		response = append(response, item)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"applications": response,
+	})
+}
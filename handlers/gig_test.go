@@ -0,0 +1,93 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"jobconnect-backend/config"
+)
+
+// companyGig creates a company owned by a new recruiter with one open gig and
+// returns the gig's ID and the recruiter's token
+func companyGig(t *testing.T, r http.Handler, email string) (uint, string) {
+	t.Helper()
+
+	ownerID, ownerToken := recruiter(t, r, email)
+	status, body := doJSON(t, r, http.MethodPost, "/api/company", ownerToken, map[string]string{"name": "Acme"})
+	if status != http.StatusCreated {
+		t.Fatalf("create company: got %d %v", status, body)
+	}
+	var owner config.User
+	config.DB.First(&owner, ownerID)
+
+	var category config.Category
+	config.DB.FirstOrCreate(&category, config.Category{Name: "Illustration", Slug: "illustration"})
+	gig := config.Gig{CompanyID: *owner.CompanyID, PostedByID: ownerID, CategoryID: category.ID, Title: "Mural", Description: "A wall", BudgetMin: 100, BudgetMax: 200, Currency: "EUR", Status: "open", Deadline: time.Now().Add(24 * time.Hour)}
+	if err := config.DB.Create(&gig).Error; err != nil {
+		t.Fatalf("create gig: %v", err)
+	}
+	return gig.ID, ownerToken
+}
+
+func TestOnlyOutsideCreativesApplyToGigs(t *testing.T) {
+	r, _ := setupServer(t)
+
+	gigID, _ := companyGig(t, r, "owner@example.com")
+	path := fmt.Sprintf("/api/gigs/%d/apply", gigID)
+
+	modToken := admin(t, r, "mod@example.com")
+	var mod config.User
+	config.DB.Where("email = ?", "mod@example.com").First(&mod)
+	status, _ := doJSON(t, r, http.MethodPost, path, modToken, map[string]interface{}{
+		"project_ids": []uint{publishedProject(t, r, mod.ID, modToken)},
+	})
+	if status != http.StatusForbidden {
+		t.Fatalf("admin applying: got %d, want 403", status)
+	}
+
+	adaID, adaToken := creative(t, r, "ada@example.com")
+	status, body := doJSON(t, r, http.MethodPost, path, adaToken, map[string]interface{}{
+		"project_ids": []uint{publishedProject(t, r, adaID, adaToken)},
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("creative applying: got %d %v", status, body)
+	}
+}
+
+func TestAcceptedApplicationsAreFinal(t *testing.T) {
+	r, mailer := setupServer(t)
+
+	gigID, ownerToken := companyGig(t, r, "owner@example.com")
+	adaID, adaToken := creative(t, r, "ada@example.com")
+	status, body := doJSON(t, r, http.MethodPost, fmt.Sprintf("/api/gigs/%d/apply", gigID), adaToken, map[string]interface{}{
+		"project_ids": []uint{publishedProject(t, r, adaID, adaToken)},
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("apply: got %d %v", status, body)
+	}
+	path := fmt.Sprintf("/api/gigs/%d/applications/%d", gigID, uint(body["application_id"].(float64)))
+
+	status, body = doJSON(t, r, http.MethodPut, path, ownerToken, map[string]string{"status": "accepted"})
+	if status != http.StatusOK {
+		t.Fatalf("accept: got %d %v", status, body)
+	}
+	sent := len(mailer.Sent)
+
+	for _, next := range []string{"submitted", "rejected"} {
+		status, _ = doJSON(t, r, http.MethodPut, path, ownerToken, map[string]string{"status": next})
+		if status != http.StatusBadRequest {
+			t.Fatalf("accepted to %s: got %d, want 400", next, status)
+		}
+	}
+	if len(mailer.Sent) != sent {
+		t.Fatalf("applicant emailed about a refused change: %v", mailer.Sent[sent:])
+	}
+
+	var application config.GigApplication
+	config.DB.Where("gig_id = ? AND applicant_id = ?", gigID, adaID).First(&application)
+	if application.Status != "accepted" {
+		t.Fatalf("status changed to %q", application.Status)
+	}
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"jobconnect-backend/config"
+	"jobconnect-backend/models"
+	"jobconnect-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImpersonateUser - Admin gets a short-lived token to act as another user for
+// support. The session can't be refreshed and every request made with it is
+// audited under the admin's ID.
+func ImpersonateUser(c *gin.Context) {
+	adminID, _ := c.Get("user_id")
+	userID := c.Param("id")
+
+	var req models.ImpersonateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user config.User
+	if err := config.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if user.ID == adminID.(uint) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot impersonate yourself"})
+		return
+	}
+	if user.Role == "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin accounts cannot be impersonated"})
+		return
+	}
+	if utils.AccountBlocked(user.Status, user.SuspendedUntil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Suspended or banned accounts cannot be impersonated"})
+		return
+	}
+
+	// The refresh token is thrown away so the session dies with the access token
+	refreshToken, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	now := time.Now()
+	impersonator := adminID.(uint)
+	session := config.Session{
+		UserID:           user.ID,
+		RefreshTokenHash: utils.HashToken(refreshToken),
+		UserAgent:        c.Request.UserAgent(),
+		IPAddress:        c.ClientIP(),
+		ExpiresAt:        now.Add(utils.ImpersonationTTL),
+		LastUsedAt:       now,
+		ImpersonatorID:   &impersonator,
+	}
+	if err := config.DB.Create(&session).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start impersonation"})
+		return
+	}
+
+	token, err := utils.GenerateImpersonationToken(user.ID, user.Email, user.Role, session.ID, impersonator)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	utils.RecordAudit(c, "impersonation.start", "user", user.ID, utils.AuditDiff{
+		"reason":     {From: nil, To: req.Reason},
+		"session_id": {From: nil, To: session.ID},
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"token":      token,
+		"expires_in": int(utils.ImpersonationTTL.Seconds()),
+		"user": models.UserResponse{
+			ID:    user.ID,
+			Name:  user.Name,
+			Email: user.Email,
+			Role:  user.Role,
+		},
+	})
+}
+
+// EndImpersonation - Admin revokes the impersonation session they're using
+func EndImpersonation(c *gin.Context) {
+	impersonatorID, ok := c.Get("impersonator_id")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Not an impersonation session"})
+		return
+	}
+	sessionID, _ := c.Get("session_id")
+	userID, _ := c.Get("user_id")
+
+	config.DB.Model(&config.Session{}).
+		Where("id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", time.Now())
+
+	utils.RecordAuditAs(c, impersonatorID.(uint), "impersonation.end", "user", userID, nil)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Impersonation ended",
+	})
+}
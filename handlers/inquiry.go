@@ -0,0 +1,354 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"jobconnect-backend/config"
+	"jobconnect-backend/models"
+	"jobconnect-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Inquiry statuses. Pending waits on the creative, countered on the company.
+const (
+	inquiryPending   = "pending"
+	inquiryCountered = "countered"
+	inquiryAccepted  = "accepted"
+	inquiryDeclined  = "declined"
+	inquiryWithdrawn = "withdrawn"
+)
+
+func inquiryResponse(inquiry config.HireInquiry) models.InquiryResponse {
+	return models.InquiryResponse{
+		ID:        inquiry.ID,
+		Company:   companyResponse(inquiry.Company),
+		Sender:    userSummary(inquiry.Sender),
+		Creative:  userSummary(inquiry.Creative),
+		Title:     inquiry.Title,
+		Brief:     inquiry.Brief,
+		BudgetMin: inquiry.BudgetMin,
+		BudgetMax: inquiry.BudgetMax,
+		Currency:  inquiry.Currency,
+		Timeline:  inquiry.Timeline,
+		Status:    inquiry.Status,
+		CreatedAt: inquiry.CreatedAt,
+		UpdatedAt: inquiry.UpdatedAt,
+	}
+}
+
+// inquiryHistory lists an inquiry's events, oldest first
+func inquiryHistory(inquiryID uint) []models.InquiryEventResponse {
+	var events []config.HireInquiryEvent
+	config.DB.Preload("Actor").Where("inquiry_id = ?", inquiryID).Order("created_at").Find(&events)
+
+	history := []models.InquiryEventResponse{}
+	for _, event := range events {
+		history = append(history, models.InquiryEventResponse{
+			ID:        event.ID,
+			Actor:     userSummary(event.Actor),
+			Status:    event.Status,
+			Message:   event.Message,
+			BudgetMin: event.BudgetMin,
+			BudgetMax: event.BudgetMax,
+			Timeline:  event.Timeline,
+			CreatedAt: event.CreatedAt,
+		})
+	}
+	return history
+}
+
+// inquiryForParty loads an inquiry the current user takes part in, either as
+// the creative or as a member of the sending company, and reports which side
+// they're on
+func inquiryForParty(c *gin.Context) (config.User, config.HireInquiry, string, bool) {
+	userID, _ := c.Get("user_id")
+
+	var user config.User
+	var inquiry config.HireInquiry
+	if err := config.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return user, inquiry, "", false
+	}
+
+	if err := config.DB.Preload("Company").Preload("Sender").Preload("Creative").
+		Where("id = ?", c.Param("id")).First(&inquiry).Error; err == nil {
+		if inquiry.CreativeID == user.ID {
+			return user, inquiry, "creative", true
+		}
+		if user.CompanyID != nil && *user.CompanyID == inquiry.CompanyID {
+			return user, inquiry, "company", true
+		}
+	}
+
+	c.JSON(http.StatusNotFound, gin.H{"error": "Inquiry not found"})
+	return user, inquiry, "", false
+}
+
+// SendInquiry - Company user sends a hire inquiry to a for-hire creative
+func SendInquiry(c *gin.Context) {
+	var req models.CreateInquiryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	// The title goes into email subjects
+	if strings.ContainsAny(req.Title, "\r\n") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Title must be a single line"})
+		return
+	}
+
+	user, ok := currentCompanyUser(c)
+	if !ok {
+		return
+	}
+
+	var creative config.User
+	if err := config.DB.Where("id = ? AND role = ? AND status NOT IN ?", req.CreativeID, "creative", []string{utils.StatusBanned, utils.StatusDeleted}).
+		First(&creative).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Creative not found"})
+		return
+	}
+	if !creative.ForHire {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This creative is not available for hire"})
+		return
+	}
+
+	inquiry := config.HireInquiry{
+		CompanyID:  *user.CompanyID,
+		SenderID:   user.ID,
+		CreativeID: creative.ID,
+		Title:      strings.TrimSpace(req.Title),
+		Brief:      strings.TrimSpace(req.Brief),
+		BudgetMin:  req.BudgetMin,
+		BudgetMax:  req.BudgetMax,
+		Currency:   strings.ToUpper(req.Currency),
+		Timeline:   strings.TrimSpace(req.Timeline),
+		Status:     inquiryPending,
+	}
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&inquiry).Error; err != nil {
+			return err
+		}
+		return tx.Create(&config.HireInquiryEvent{
+			InquiryID: inquiry.ID,
+			ActorID:   user.ID,
+			Status:    inquiryPending,
+			BudgetMin: &inquiry.BudgetMin,
+			BudgetMax: &inquiry.BudgetMax,
+			Timeline:  inquiry.Timeline,
+		}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send inquiry"})
+		return
+	}
+
+	body := fmt.Sprintf(
+		"Hi %s,\n\n%s from %s would like to hire you for \"%s\" (%s %d-%d, %s).\n\nReply from your inquiry inbox on RosyArtGrid.",
+		creative.Name, user.Name, user.Company.Name, inquiry.Title, inquiry.Currency, inquiry.BudgetMin, inquiry.BudgetMax, inquiry.Timeline,
+	)
+	if err := utils.GetMailer().Send(creative.Email, "New hire inquiry from "+user.Company.Name, body); err != nil {
+		log.Println("Warning: Failed to send inquiry email:", err)
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":    true,
+		"message":    "Inquiry sent",
+		"inquiry_id": inquiry.ID,
+	})
+}
+
+// GetInquiries - Inquiry inbox: those received as a creative and those sent
+// by the user's company. Filter with ?box=received|sent and ?status=.
+func GetInquiries(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	var user config.User
+	if err := config.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	query := config.DB.Model(&config.HireInquiry{})
+	switch box := c.Query("box"); {
+	case box == "received":
+		query = query.Where("creative_id = ?", user.ID)
+	case box == "sent" && user.CompanyID != nil:
+		query = query.Where("company_id = ?", *user.CompanyID)
+	case box == "sent":
+		query = query.Where("1 = 0")
+	case user.CompanyID != nil:
+		query = query.Where("creative_id = ? OR company_id = ?", user.ID, *user.CompanyID)
+	default:
+		query = query.Where("creative_id = ?", user.ID)
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var totalCount int64
+	query.Count(&totalCount)
+
+	var inquiries []config.HireInquiry
+	query.Preload("Company").Preload("Sender").Preload("Creative").
+		Order("updated_at DESC").Offset(offset).Limit(limit).Find(&inquiries)
+
+	response := []models.InquiryResponse{}
+	for _, inquiry := range inquiries {
+		response = append(response, inquiryResponse(inquiry))
+	}
+
+	totalPages := int((totalCount + int64(limit) - 1) / int64(limit))
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"inquiries":  response,
+		"page":       page,
+		"totalPages": totalPages,
+		"totalCount": totalCount,
+	})
+}
+
+// GetInquiry - An inquiry with its status history
+func GetInquiry(c *gin.Context) {
+	_, inquiry, _, ok := inquiryForParty(c)
+	if !ok {
+		return
+	}
+
+	response := inquiryResponse(inquiry)
+	response.History = inquiryHistory(inquiry.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"inquiry": response,
+	})
+}
+
+// RespondToInquiry - Accept, decline or counter an inquiry when it's your
+// turn, or withdraw it as the company while it's still open
+func RespondToInquiry(c *gin.Context) {
+	var req models.RespondInquiryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, inquiry, side, ok := inquiryForParty(c)
+	if !ok {
+		return
+	}
+
+	if inquiry.Status != inquiryPending && inquiry.Status != inquiryCountered {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This inquiry is already " + inquiry.Status})
+		return
+	}
+
+	turn := "creative"
+	if inquiry.Status == inquiryCountered {
+		turn = "company"
+	}
+
+	event := config.HireInquiryEvent{
+		InquiryID: inquiry.ID,
+		ActorID:   user.ID,
+		Message:   strings.TrimSpace(req.Message),
+	}
+	updates := map[string]interface{}{}
+
+	switch req.Action {
+	case "withdraw":
+		if side != "company" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the company can withdraw an inquiry"})
+			return
+		}
+		event.Status = inquiryWithdrawn
+	case "accept", "decline", "counter":
+		if side != turn {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Waiting for the " + turn + " to respond"})
+			return
+		}
+		switch req.Action {
+		case "accept":
+			event.Status = inquiryAccepted
+		case "decline":
+			event.Status = inquiryDeclined
+		case "counter":
+			timeline := strings.TrimSpace(req.Timeline)
+			if req.BudgetMin == nil || req.BudgetMax == nil || timeline == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "A counter-offer needs budget_min, budget_max and timeline"})
+				return
+			}
+			if *req.BudgetMax < *req.BudgetMin {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "budget_max must be at least budget_min"})
+				return
+			}
+			// Countering hands the turn to the other side
+			event.Status = inquiryCountered
+			if side == "company" {
+				event.Status = inquiryPending
+			}
+			event.BudgetMin = req.BudgetMin
+			event.BudgetMax = req.BudgetMax
+			event.Timeline = timeline
+			updates["budget_min"] = *req.BudgetMin
+			updates["budget_max"] = *req.BudgetMax
+			updates["timeline"] = timeline
+		}
+	}
+	updates["status"] = event.Status
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		// Only apply if nobody else answered in the meantime
+		result := tx.Model(&config.HireInquiry{}).Where("id = ? AND status = ?", inquiry.ID, inquiry.Status).Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Create(&event).Error
+	})
+	if err == gorm.ErrRecordNotFound {
+		c.JSON(http.StatusConflict, gin.H{"error": "The inquiry changed in the meantime, reload it"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update inquiry"})
+		return
+	}
+
+	// Let the other side know
+	recipient := inquiry.Creative
+	if side == "creative" {
+		recipient = inquiry.Sender
+	}
+	body := fmt.Sprintf(
+		"Hi %s,\n\n%s changed the inquiry \"%s\" to %s.\n\nOpen your inquiry inbox on RosyArtGrid for details.",
+		recipient.Name, user.Name, inquiry.Title, event.Status,
+	)
+	if err := utils.GetMailer().Send(recipient.Email, "Update on \""+inquiry.Title+"\"", body); err != nil {
+		log.Println("Warning: Failed to send inquiry email:", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Inquiry " + event.Status,
+		"status":  event.Status,
+	})
+}
@@ -0,0 +1,40 @@
+package handlers_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"jobconnect-backend/config"
+)
+
+func TestInquiryTitleMustBeOneLine(t *testing.T) {
+	r, mailer := setupServer(t)
+
+	_, ownerToken := recruiter(t, r, "owner@example.com")
+	doJSON(t, r, http.MethodPost, "/api/company", ownerToken, map[string]string{"name": "Acme"})
+	adaID, _ := creative(t, r, "ada@example.com")
+	config.DB.Model(&config.User{}).Where("id = ?", adaID).Update("for_hire", true)
+
+	inquiry := map[string]interface{}{
+		"creative_id": adaID,
+		"title":       "Album cover\r\nBcc: eve@example.com",
+		"brief":       "Twelve inches",
+		"budget_max":  800,
+		"currency":    "EUR",
+		"timeline":    "By June",
+	}
+	status, _ := doJSON(t, r, http.MethodPost, "/api/inquiries", ownerToken, inquiry)
+	if status != http.StatusBadRequest {
+		t.Fatalf("title with a line break: got %d, want 400", status)
+	}
+
+	inquiry["title"] = "Album cover"
+	status, body := doJSON(t, r, http.MethodPost, "/api/inquiries", ownerToken, inquiry)
+	if status != http.StatusCreated {
+		t.Fatalf("single-line title: got %d %v", status, body)
+	}
+	if sent, ok := mailer.Last("ada@example.com"); !ok || !strings.Contains(sent.Subject, "Acme") {
+		t.Fatalf("creative not notified: %+v", sent)
+	}
+}
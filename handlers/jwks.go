@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"net/http"
+
+	"jobconnect-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetJWKS - Publish the public token verification keys
+func GetJWKS(c *gin.Context) {
+	keys, err := utils.PublicJWKS()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load signing keys"})
+		return
+	}
+
+	// Partner services may cache the set briefly; rotation adds the new key
+	// well before it becomes active
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{"keys": keys})
+}
@@ -0,0 +1,152 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"testing"
+
+	"jobconnect-backend/config"
+	"jobconnect-backend/routes"
+	"jobconnect-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestMain(m *testing.M) {
+	// HS256 keeps the tests free of key files; login attempts stay in memory
+	os.Setenv("JWT_KEY_DIR", "")
+	os.Setenv("JWT_SECRET", "test-secret")
+	os.Setenv("LOGIN_ATTEMPT_STORE", "memory")
+	os.Setenv("FRONTEND_URL", "http://frontend.test")
+	os.Setenv("SMTP_HOST", "")
+	gin.SetMode(gin.TestMode)
+
+	os.Exit(m.Run())
+}
+
+// setupServer points the app at a fresh in-memory database and mailer and
+// returns a router with every route registered
+func setupServer(t *testing.T) (*gin.Engine, *utils.MemoryMailer) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent), TranslateError: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every connection to :memory: is its own database, so keep just one
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(
+		&config.User{},
+		&config.Company{},
+		&config.Project{},
+		&config.ProjectImage{},
+		&config.UploadedImage{},
+		&config.ProjectRevision{},
+		&config.Like{},
+		&config.Comment{},
+		&config.Follow{},
+		&config.Category{},
+		&config.Session{},
+		&config.PasswordResetToken{},
+		&config.LoginAttempt{},
+		&config.RecoveryCode{},
+		&config.UserIdentity{},
+		&config.OIDCLoginState{},
+		&config.APIKey{},
+		&config.RolePermission{},
+		&config.AccountStatusChange{},
+		&config.AuditEvent{},
+		&config.HandleRedirect{},
+		&config.CompanyInvitation{},
+		&config.HireInquiry{},
+		&config.HireInquiryEvent{},
+		&config.AvailabilityWindow{},
+		&config.RateCard{},
+		&config.Gig{},
+		&config.GigApplication{},
+		&config.GigApplicationProject{},
+	); err != nil {
+		t.Fatal(err)
+	}
+	config.DB = db
+	utils.SeedPermissions()
+
+	mailer := &utils.MemoryMailer{}
+	utils.SetMailer(mailer)
+	utils.SetLoginLimiter(&utils.LoginLimiter{Store: utils.NewMemoryAttemptStore()})
+
+	r := gin.New()
+	routes.SetupRoutes(r)
+	return r, mailer
+}
+
+// doJSON sends a JSON request, with a bearer token when one is given, and
+// decodes the JSON response
+func doJSON(t *testing.T, r http.Handler, method, path, token string, body interface{}) (int, map[string]interface{}) {
+	t.Helper()
+
+	status, response, _ := doJSONWithCookies(t, r, method, path, token, body, nil)
+	return status, response
+}
+
+// doJSONWithCookies is doJSON from a browser holding cookies; it also returns
+// the cookies the response set
+func doJSONWithCookies(t *testing.T, r http.Handler, method, path, token string, body interface{}, cookies []*http.Cookie) (int, map[string]interface{}, []*http.Cookie) {
+	t.Helper()
+
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	response := map[string]interface{}{}
+	if w.Body.Len() > 0 {
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("%s %s: response is not JSON: %s", method, path, w.Body.String())
+		}
+	}
+	return w.Code, response, w.Result().Cookies()
+}
+
+var linkToken = regexp.MustCompile(`\?token=([A-Za-z0-9._-]+)`)
+
+// mailedToken returns the token from the link in the last mail sent to addr
+func mailedToken(t *testing.T, mailer *utils.MemoryMailer, addr string) string {
+	t.Helper()
+
+	sent, ok := mailer.Last(addr)
+	if !ok {
+		t.Fatalf("no mail sent to %s", addr)
+	}
+	match := linkToken.FindStringSubmatch(sent.Body)
+	if match == nil {
+		t.Fatalf("no link in mail to %s: %q", addr, sent.Body)
+	}
+	return match[1]
+}
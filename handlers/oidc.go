@@ -0,0 +1,333 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"jobconnect-backend/config"
+	"jobconnect-backend/models"
+	"jobconnect-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const oidcStateTTL = 10 * time.Minute
+
+// oidcStateCookie binds a pending login to the browser that started it, so a
+// code and state obtained by someone else can't be completed in a victim's
+// browser. It holds the hash of the state.
+const oidcStateCookie = "oidc_state"
+
+func setOIDCStateCookie(c *gin.Context, value string, maxAge int) {
+	// The frontend calls the API from its own origin, which needs SameSite=None;
+	// browsers only accept that on secure cookies
+	secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+	if secure {
+		c.SetSameSite(http.SameSiteNoneMode)
+	} else {
+		c.SetSameSite(http.SameSiteLaxMode)
+	}
+	c.SetCookie(oidcStateCookie, value, maxAge, "/api/auth/oidc", "", secure, true)
+}
+
+// beginOIDC records a pending login and returns the provider's authorization URL
+func beginOIDC(c *gin.Context, linkUserID *uint) {
+	provider, ok := utils.GetAuthProvider(c.Param("provider"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown login provider"})
+		return
+	}
+
+	state, err1 := utils.GenerateOpaqueToken()
+	verifier, err2 := utils.GenerateOpaqueToken()
+	nonce, err3 := utils.GenerateOpaqueToken()
+	if err1 != nil || err2 != nil || err3 != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start login"})
+		return
+	}
+
+	loginState := config.OIDCLoginState{
+		StateHash:    utils.HashToken(state),
+		Provider:     provider.Name(),
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		LinkUserID:   linkUserID,
+		ExpiresAt:    time.Now().Add(oidcStateTTL),
+	}
+	if err := config.DB.Create(&loginState).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start login"})
+		return
+	}
+
+	authURL, err := provider.AuthCodeURL(c.Request.Context(), state, utils.PKCEChallenge(verifier), nonce)
+	if err != nil {
+		log.Println("Warning: OIDC provider unavailable:", err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Login provider unavailable"})
+		return
+	}
+
+	setOIDCStateCookie(c, loginState.StateHash, int(oidcStateTTL.Seconds()))
+	c.JSON(http.StatusOK, gin.H{
+		"success":           true,
+		"authorization_url": authURL,
+	})
+}
+
+// StartOIDCLogin - Begin social login with an external provider
+func StartOIDCLogin(c *gin.Context) {
+	beginOIDC(c, nil)
+}
+
+// LinkOIDCIdentity - Begin linking an external account to the current user
+func LinkOIDCIdentity(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	id := userID.(uint)
+	beginOIDC(c, &id)
+}
+
+// OIDCCallback - Finish social login with the code and state the provider
+// redirected back to the frontend with
+func OIDCCallback(c *gin.Context) {
+	var req models.OIDCCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	provider, ok := utils.GetAuthProvider(c.Param("provider"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown login provider"})
+		return
+	}
+
+	// The login must finish in the browser that started it
+	stateHash := utils.HashToken(req.State)
+	cookie, err := c.Cookie(oidcStateCookie)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie), []byte(stateHash)) != 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired login state"})
+		return
+	}
+	setOIDCStateCookie(c, "", -1)
+
+	// States are single-use: delete and read in one statement
+	var loginState config.OIDCLoginState
+	result := config.DB.Clauses(clause.Returning{}).
+		Where("state_hash = ? AND provider = ? AND expires_at > ?", stateHash, provider.Name(), time.Now()).
+		Delete(&loginState)
+	if result.Error != nil || result.RowsAffected == 0 || loginState.ID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired login state"})
+		return
+	}
+
+	identity, err := provider.Exchange(c.Request.Context(), req.Code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		log.Println("Warning: OIDC exchange failed:", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Login with provider failed"})
+		return
+	}
+
+	user, err := resolveIdentityUser(identity, loginState.LinkUserID)
+	if errors.Is(err, errIdentityTaken) {
+		c.JSON(http.StatusConflict, gin.H{"error": "This account is already linked to another user"})
+		return
+	}
+	if errors.Is(err, errEmailNotVerified) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Your " + provider.Name() + " account has no verified email address"})
+		return
+	}
+	if errors.Is(err, errLinkRequired) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":         "An account with this email already exists. Sign in with your password and link " + provider.Name() + " from your profile.",
+			"link_required": true,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to sign in"})
+		return
+	}
+
+	if loginState.LinkUserID != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"message": "Account linked",
+		})
+		return
+	}
+
+	finishPrimaryAuth(c, user)
+}
+
+var (
+	errIdentityTaken    = errors.New("identity already linked to another user")
+	errEmailNotVerified = errors.New("provider did not return a verified email")
+	errLinkRequired     = errors.New("account with this email must link the identity while signed in")
+	errLastLogin        = errors.New("identity is the account's only way to log in")
+)
+
+// resolveIdentityUser maps an external identity to a user: an existing link,
+// the user being linked, an existing account with the same verified email,
+// or a brand new account. An existing account whose owner never verified the
+// email isn't linked automatically: whoever registered it may not own the
+// address, and would keep access through the password they chose.
+func resolveIdentityUser(identity *utils.ExternalIdentity, linkUserID *uint) (config.User, error) {
+	var user config.User
+
+	var link config.UserIdentity
+	err := config.DB.Where("provider = ? AND subject = ?", identity.Provider, identity.Subject).First(&link).Error
+	if err == nil {
+		if linkUserID != nil && *linkUserID != link.UserID {
+			return user, errIdentityTaken
+		}
+		err = config.DB.Preload("Company").Where("id = ?", link.UserID).First(&user).Error
+		return user, err
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return user, err
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		switch {
+		case linkUserID != nil:
+			if err := tx.Where("id = ?", *linkUserID).First(&user).Error; err != nil {
+				return err
+			}
+		case identity.EmailVerified && identity.Email != "":
+			// Only trust the provider's email for matching when it verified it
+			err := tx.Where("LOWER(email) = ?", strings.ToLower(identity.Email)).First(&user).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				user, err = createIdentityUser(tx, identity)
+			}
+			if err != nil {
+				return err
+			}
+			if !user.Verified {
+				return errLinkRequired
+			}
+		default:
+			return errEmailNotVerified
+		}
+
+		return tx.Create(&config.UserIdentity{
+			UserID:   user.ID,
+			Provider: identity.Provider,
+			Subject:  identity.Subject,
+			Email:    identity.Email,
+		}).Error
+	})
+	if err != nil {
+		return user, err
+	}
+
+	err = config.DB.Preload("Company").Where("id = ?", user.ID).First(&user).Error
+	return user, err
+}
+
+// createIdentityUser registers a new creative from an external identity. The
+// password is random, so the account can only log in via the provider until
+// the user sets one through the reset flow.
+func createIdentityUser(tx *gorm.DB, identity *utils.ExternalIdentity) (config.User, error) {
+	randomPassword, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return config.User{}, err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(randomPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return config.User{}, err
+	}
+
+	name := identity.Name
+	if name == "" {
+		name = strings.Split(identity.Email, "@")[0]
+	}
+
+	user := config.User{
+		Name:       name,
+		Email:      identity.Email,
+		Password:   string(hashedPassword),
+		NoPassword: true,
+		Role:       "creative",
+		AvatarURL:  identity.Picture,
+		Verified:   identity.EmailVerified,
+	}
+	err = tx.Create(&user).Error
+	return user, err
+}
+
+// GetMyIdentities - List external accounts linked to the current user
+func GetMyIdentities(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var identities []config.UserIdentity
+	config.DB.Where("user_id = ?", userID).Order("created_at").Find(&identities)
+
+	var response []models.IdentityResponse
+	for _, identity := range identities {
+		response = append(response, models.IdentityResponse{
+			ID:        identity.ID,
+			Provider:  identity.Provider,
+			Email:     identity.Email,
+			CreatedAt: identity.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"identities": response,
+	})
+}
+
+// UnlinkIdentity - Remove a linked external account
+func UnlinkIdentity(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	identityID := c.Param("id")
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		// Lock the user so two unlinks can't both leave the other one behind
+		var user config.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", userID).First(&user).Error; err != nil {
+			return err
+		}
+
+		var remaining int64
+		if err := tx.Model(&config.UserIdentity{}).Where("user_id = ? AND id <> ?", userID, identityID).Count(&remaining).Error; err != nil {
+			return err
+		}
+		if remaining == 0 && user.NoPassword {
+			return errLastLogin
+		}
+
+		result := tx.Where("id = ? AND user_id = ?", identityID, userID).Delete(&config.UserIdentity{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if errors.Is(err, errLastLogin) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Set a password before unlinking your only login provider"})
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Identity not found or unauthorized"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unlink account"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Account unlinked",
+	})
+}
@@ -0,0 +1,432 @@
+package handlers_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"jobconnect-backend/config"
+	"jobconnect-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// mockOIDC is a minimal OpenID Connect provider: discovery, token and JWKS
+// endpoints. The browser step is replaced by approve, which hands out a code
+// for the identity the test wants to sign in as.
+type mockOIDC struct {
+	*httptest.Server
+	t   *testing.T
+	key *rsa.PrivateKey
+
+	mu     sync.Mutex
+	issued int
+	codes  map[string]mockGrant
+}
+
+type mockGrant struct {
+	challenge string
+	nonce     string
+	claims    jwt.MapClaims
+}
+
+const (
+	mockClientID     = "rosyartgrid-test"
+	mockClientSecret = "mock-secret"
+)
+
+// newMockOIDC starts a provider and registers it with the app as "mock"
+func newMockOIDC(t *testing.T) *mockOIDC {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &mockOIDC{t: t, key: key, codes: make(map[string]mockGrant)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                                m.URL,
+			"authorization_endpoint":                m.URL + "/authorize",
+			"token_endpoint":                        m.URL + "/token",
+			"jwks_uri":                              m.URL + "/jwks",
+			"token_endpoint_auth_methods_supported": []string{"client_secret_basic"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "mock-key",
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", m.token)
+	m.Server = httptest.NewServer(mux)
+	t.Cleanup(m.Close)
+
+	utils.RegisterAuthProvider(&utils.OIDCProvider{
+		ProviderName: "mock",
+		Issuer:       m.URL,
+		ClientID:     mockClientID,
+		ClientSecret: mockClientSecret,
+		RedirectURL:  "http://frontend.test/auth/callback/mock",
+		Scopes:       []string{"openid", "email", "profile"},
+	})
+	return m
+}
+
+// approve plays the user consenting at the provider: it checks the
+// authorization request and returns the code and state the provider would
+// redirect back with
+func (m *mockOIDC) approve(authURL string, claims jwt.MapClaims) (code, state string) {
+	m.t.Helper()
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	q := u.Query()
+	if u.Path != "/authorize" || q.Get("client_id") != mockClientID || q.Get("response_type") != "code" {
+		m.t.Fatalf("unexpected authorization request %s", authURL)
+	}
+	if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" || q.Get("state") == "" || q.Get("nonce") == "" {
+		m.t.Fatalf("authorization request without PKCE, state or nonce: %s", authURL)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.issued++
+	code = "code-" + strconv.Itoa(m.issued)
+	m.codes[code] = mockGrant{challenge: q.Get("code_challenge"), nonce: q.Get("nonce"), claims: claims}
+	return code, q.Get("state")
+}
+
+func (m *mockOIDC) token(w http.ResponseWriter, r *http.Request) {
+	fail := func(reason string) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant", "error_description": reason})
+	}
+
+	clientID, secret, ok := r.BasicAuth()
+	if !ok || clientID != mockClientID || secret != mockClientSecret {
+		fail("bad client credentials")
+		return
+	}
+	if r.FormValue("grant_type") != "authorization_code" {
+		fail("bad grant type")
+		return
+	}
+
+	m.mu.Lock()
+	grant, ok := m.codes[r.FormValue("code")]
+	delete(m.codes, r.FormValue("code"))
+	m.mu.Unlock()
+	if !ok {
+		fail("unknown code")
+		return
+	}
+	if utils.PKCEChallenge(r.FormValue("code_verifier")) != grant.challenge {
+		fail("code_verifier does not match code_challenge")
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":   m.URL,
+		"aud":   mockClientID,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"nonce": grant.nonce,
+	}
+	for k, v := range grant.claims {
+		claims[k] = v
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "mock-key"
+	idToken, err := token.SignedString(m.key)
+	if err != nil {
+		m.t.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"access_token": "mock-access", "token_type": "Bearer", "id_token": idToken})
+}
+
+// startOIDC begins a login (or, with a token, a link) and returns the
+// provider's authorization URL and the cookies binding the login to this
+// browser
+func startOIDC(t *testing.T, r *gin.Engine, token string) (string, []*http.Cookie) {
+	t.Helper()
+
+	method, path := http.MethodGet, "/api/auth/oidc/mock"
+	if token != "" {
+		method, path = http.MethodPost, "/api/auth/oidc/mock/link"
+	}
+	status, body, cookies := doJSONWithCookies(t, r, method, path, token, nil, nil)
+	if status != http.StatusOK {
+		t.Fatalf("start: got %d %v", status, body)
+	}
+	return body["authorization_url"].(string), cookies
+}
+
+// oidcCallback finishes a login from a browser holding cookies
+func oidcCallback(t *testing.T, r *gin.Engine, cookies []*http.Cookie, code, state string) (int, map[string]interface{}) {
+	t.Helper()
+
+	status, body, _ := doJSONWithCookies(t, r, http.MethodPost, "/api/auth/oidc/mock/callback", "", map[string]string{"code": code, "state": state}, cookies)
+	return status, body
+}
+
+// oidcLogin runs a whole login as the given identity and returns the
+// callback's response
+func oidcLogin(t *testing.T, r *gin.Engine, provider *mockOIDC, claims jwt.MapClaims) (int, map[string]interface{}) {
+	t.Helper()
+
+	authURL, cookies := startOIDC(t, r, "")
+	code, state := provider.approve(authURL, claims)
+	return oidcCallback(t, r, cookies, code, state)
+}
+
+func loggedInUserID(t *testing.T, body map[string]interface{}) uint {
+	t.Helper()
+
+	if body["token"] == nil {
+		t.Fatalf("no access token in %v", body)
+	}
+	return uint(body["user"].(map[string]interface{})["id"].(float64))
+}
+
+func countIdentities(t *testing.T) int64 {
+	t.Helper()
+
+	var count int64
+	config.DB.Model(&config.UserIdentity{}).Count(&count)
+	return count
+}
+
+func TestOIDCLoginRoundTrip(t *testing.T) {
+	r, _ := setupServer(t)
+	provider := newMockOIDC(t)
+
+	authURL, cookies := startOIDC(t, r, "")
+	code, state := provider.approve(authURL, jwt.MapClaims{
+		"sub":            "mock-123",
+		"email":          "grace@example.com",
+		"email_verified": true,
+		"name":           "Grace",
+	})
+
+	// A state we never issued is refused before the code is redeemed
+	status, _ := oidcCallback(t, r, cookies, code, "forged")
+	if status != http.StatusBadRequest {
+		t.Fatalf("forged state: got %d, want 400", status)
+	}
+
+	status, body := oidcCallback(t, r, cookies, code, state)
+	if status != http.StatusOK {
+		t.Fatalf("callback: got %d %v", status, body)
+	}
+	userID := loggedInUserID(t, body)
+
+	var user config.User
+	config.DB.First(&user, userID)
+	if user.Email != "grace@example.com" || user.Name != "Grace" || !user.Verified {
+		t.Fatalf("unexpected new user %+v", user)
+	}
+	var identity config.UserIdentity
+	if err := config.DB.Where("provider = ? AND subject = ?", "mock", "mock-123").First(&identity).Error; err != nil || identity.UserID != userID {
+		t.Fatalf("identity not linked: %+v %v", identity, err)
+	}
+
+	// States are single-use
+	status, _ = oidcCallback(t, r, cookies, code, state)
+	if status != http.StatusBadRequest {
+		t.Fatalf("reused state: got %d, want 400", status)
+	}
+
+	// The same identity signs in to the same account next time
+	status, body = oidcLogin(t, r, provider, jwt.MapClaims{"sub": "mock-123", "email": "grace@example.com", "email_verified": true})
+	if status != http.StatusOK || loggedInUserID(t, body) != userID {
+		t.Fatalf("second login: got %d %v", status, body)
+	}
+}
+
+func TestOIDCRejectsCodeFromAnotherLogin(t *testing.T) {
+	r, _ := setupServer(t)
+	provider := newMockOIDC(t)
+
+	// A code issued for one login can't be redeemed with another login's
+	// state: its PKCE verifier doesn't match the code's challenge
+	authURL, _ := startOIDC(t, r, "")
+	code, _ := provider.approve(authURL, jwt.MapClaims{"sub": "mock-123", "email": "grace@example.com", "email_verified": true})
+	otherURL, otherCookies := startOIDC(t, r, "")
+	_, otherState := provider.approve(otherURL, jwt.MapClaims{"sub": "mock-456"})
+
+	status, _ := oidcCallback(t, r, otherCookies, code, otherState)
+	if status != http.StatusUnauthorized {
+		t.Fatalf("mismatched verifier: got %d, want 401", status)
+	}
+	if countIdentities(t) != 0 {
+		t.Fatal("identity linked despite failed exchange")
+	}
+}
+
+func TestOIDCRejectsUnverifiedProviderEmail(t *testing.T) {
+	r, _ := setupServer(t)
+	provider := newMockOIDC(t)
+
+	for _, verified := range []interface{}{false, "false", nil} {
+		claims := jwt.MapClaims{"sub": "mock-123", "email": "grace@example.com"}
+		if verified != nil {
+			claims["email_verified"] = verified
+		}
+		status, body := oidcLogin(t, r, provider, claims)
+		if status != http.StatusForbidden {
+			t.Fatalf("email_verified=%v: got %d %v", verified, status, body)
+		}
+	}
+
+	var users int64
+	config.DB.Model(&config.User{}).Count(&users)
+	if users != 0 || countIdentities(t) != 0 {
+		t.Fatalf("created %d users and %d identities from unverified emails", users, countIdentities(t))
+	}
+}
+
+func TestOIDCLinkIdentity(t *testing.T) {
+	r, _ := setupServer(t)
+	provider := newMockOIDC(t)
+
+	userID := register(t, r, "ada@example.com")
+	token := login(t, r, "ada@example.com")
+
+	// The provider account may use a different address than the local one
+	authURL, cookies := startOIDC(t, r, token)
+	code, state := provider.approve(authURL, jwt.MapClaims{"sub": "mock-ada", "email": "ada@work.example", "email_verified": true})
+	status, body := oidcCallback(t, r, cookies, code, state)
+	if status != http.StatusOK || body["message"] != "Account linked" {
+		t.Fatalf("link: got %d %v", status, body)
+	}
+	if body["token"] != nil {
+		t.Fatal("linking handed out a new session")
+	}
+
+	status, body = oidcLogin(t, r, provider, jwt.MapClaims{"sub": "mock-ada", "email": "ada@work.example", "email_verified": true})
+	if status != http.StatusOK || loggedInUserID(t, body) != userID {
+		t.Fatalf("login with linked identity: got %d %v", status, body)
+	}
+
+	// Another user can't take over the linked identity
+	register(t, r, "eve@example.com")
+	authURL, cookies = startOIDC(t, r, login(t, r, "eve@example.com"))
+	code, state = provider.approve(authURL, jwt.MapClaims{"sub": "mock-ada", "email": "ada@work.example", "email_verified": true})
+	status, _ = oidcCallback(t, r, cookies, code, state)
+	if status != http.StatusConflict {
+		t.Fatalf("linking a taken identity: got %d, want 409", status)
+	}
+	var identity config.UserIdentity
+	config.DB.Where("subject = ?", "mock-ada").First(&identity)
+	if identity.UserID != userID {
+		t.Fatalf("identity moved to user %d", identity.UserID)
+	}
+}
+
+func TestOIDCDoesNotAutoLinkUnverifiedAccount(t *testing.T) {
+	r, _ := setupServer(t)
+	provider := newMockOIDC(t)
+
+	// Someone registers the victim's address with a password they know and
+	// never verifies it
+	squatterID := register(t, r, "victim@example.com")
+
+	status, body := oidcLogin(t, r, provider, jwt.MapClaims{"sub": "mock-victim", "email": "victim@example.com", "email_verified": true})
+	if status != http.StatusConflict || body["link_required"] != true {
+		t.Fatalf("login onto unverified account: got %d %v", status, body)
+	}
+	if countIdentities(t) != 0 {
+		t.Fatal("identity linked to the unverified account")
+	}
+	var sessions int64
+	config.DB.Model(&config.Session{}).Where("user_id = ?", squatterID).Count(&sessions)
+	if sessions != 1 {
+		t.Fatalf("expected only the registration session, got %d", sessions)
+	}
+
+	// Once the address is verified, the same login links automatically
+	config.DB.Model(&config.User{}).Where("id = ?", squatterID).Update("verified", true)
+	status, body = oidcLogin(t, r, provider, jwt.MapClaims{"sub": "mock-victim", "email": "Victim@example.com", "email_verified": true})
+	if status != http.StatusOK || loggedInUserID(t, body) != squatterID {
+		t.Fatalf("login onto verified account: got %d %v", status, body)
+	}
+}
+
+func TestOIDCCallbackNeedsTheStartingBrowser(t *testing.T) {
+	r, _ := setupServer(t)
+	provider := newMockOIDC(t)
+
+	// An attacker starts a link to their own provider account and gets the
+	// victim's browser to finish it
+	register(t, r, "victim@example.com")
+	victimToken := login(t, r, "victim@example.com")
+	_, victimCookies := startOIDC(t, r, "")
+
+	register(t, r, "eve@example.com")
+	authURL, _ := startOIDC(t, r, login(t, r, "eve@example.com"))
+	code, state := provider.approve(authURL, jwt.MapClaims{"sub": "mock-eve", "email": "eve@example.com", "email_verified": true})
+
+	for name, cookies := range map[string][]*http.Cookie{"no cookie": nil, "another login's cookie": victimCookies} {
+		status, _, _ := doJSONWithCookies(t, r, http.MethodPost, "/api/auth/oidc/mock/callback", victimToken, map[string]string{"code": code, "state": state}, cookies)
+		if status != http.StatusBadRequest {
+			t.Fatalf("%s: got %d, want 400", name, status)
+		}
+	}
+	if countIdentities(t) != 0 {
+		t.Fatal("identity linked from another browser")
+	}
+}
+
+func TestUnlinkKeepsAWayToLogIn(t *testing.T) {
+	r, mailer := setupServer(t)
+	provider := newMockOIDC(t)
+
+	status, body := oidcLogin(t, r, provider, jwt.MapClaims{"sub": "mock-grace", "email": "grace@example.com", "email_verified": true})
+	if status != http.StatusOK {
+		t.Fatalf("login: got %d %v", status, body)
+	}
+	token := body["token"].(string)
+
+	var identity config.UserIdentity
+	config.DB.Where("subject = ?", "mock-grace").First(&identity)
+	path := fmt.Sprintf("/api/profile/identities/%d", identity.ID)
+
+	status, _ = doJSON(t, r, http.MethodDelete, path, token, nil)
+	if status != http.StatusBadRequest || countIdentities(t) != 1 {
+		t.Fatalf("unlinking the only login: got %d, want 400", status)
+	}
+
+	// Once there's a password, the provider can go
+	doJSON(t, r, http.MethodPost, "/api/auth/forgot-password", "", map[string]string{"email": "grace@example.com"})
+	status, body = doJSON(t, r, http.MethodPost, "/api/auth/reset-password", "", map[string]string{"token": mailedToken(t, mailer, "grace@example.com"), "new_password": "secret123"})
+	if status != http.StatusOK {
+		t.Fatalf("reset password: got %d %v", status, body)
+	}
+	status, _ = doJSON(t, r, http.MethodDelete, path, login(t, r, "grace@example.com"), nil)
+	if status != http.StatusOK || countIdentities(t) != 0 {
+		t.Fatalf("unlinking with a password set: got %d, want 200", status)
+	}
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"jobconnect-backend/config"
+	"jobconnect-backend/models"
+	"jobconnect-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const passwordResetTTL = time.Hour
+
+// ForgotPassword - Email a single-use password reset link
+func ForgotPassword(c *gin.Context) {
+	var req models.EmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if mailThrottled(c, "reset", req.Email) {
+		return
+	}
+
+	var user config.User
+	if err := config.DB.Where("email = ?", req.Email).First(&user).Error; err == nil {
+		if err := sendPasswordResetEmail(user); err != nil {
+			log.Println("Warning: Failed to send password reset email:", err)
+		}
+	}
+
+	// Same response whether or not the account exists
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "If the account exists, a password reset email has been sent",
+	})
+}
+
+// mailThrottled counts a request to email a link of the given kind to the
+// address, and answers 429 when the address or the caller's IP has asked too
+// often
+func mailThrottled(c *gin.Context, kind, email string) bool {
+	limiter := utils.GetLoginLimiter()
+	retryAfter, err := limiter.MailRetryAfter(kind, email, c.ClientIP())
+	if err != nil {
+		log.Println("Warning: Failed to check mail limits:", err)
+	}
+	if retryAfter > 0 {
+		tooManyRequests(c, retryAfter, "Too many emails requested, please try again later")
+		return true
+	}
+	if err := limiter.RecordMail(kind, email, c.ClientIP()); err != nil {
+		log.Println("Warning: Failed to record mail request:", err)
+	}
+	return false
+}
+
+func sendPasswordResetEmail(user config.User) error {
+	token, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	resetToken := config.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(passwordResetTTL),
+	}
+	if err := config.DB.Create(&resetToken).Error; err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(
+		"Hi %s,\n\nSomeone asked to reset the password for your RosyArtGrid account. If that was you, use this link within %d minutes:\n\n%s\n\nIf not, you can ignore this email.",
+		user.Name, int(passwordResetTTL.Minutes()), utils.FrontendLink("/reset-password", token),
+	)
+	return utils.GetMailer().Send(user.Email, "Reset your RosyArtGrid password", body)
+}
+
+// ResetPassword - Set a new password using a reset token
+func ResetPassword(c *gin.Context) {
+	var req models.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		return
+	}
+
+	now := time.Now()
+	var resetToken config.PasswordResetToken
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		// Claim the token; the used_at condition makes it single-use even under races
+		result := tx.Model(&config.PasswordResetToken{}).
+			Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", utils.HashToken(req.Token), now).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Where("token_hash = ?", utils.HashToken(req.Token)).First(&resetToken).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&config.User{}).Where("id = ?", resetToken.UserID).
+			Updates(map[string]interface{}{"password": string(hashedPassword), "no_password": false}).Error; err != nil {
+			return err
+		}
+
+		// Burn any other outstanding reset links for this user
+		return tx.Model(&config.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", resetToken.UserID).
+			Update("used_at", now).Error
+	})
+	if err == gorm.ErrRecordNotFound {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired reset token"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+		return
+	}
+
+	// Whoever had the old password must not keep their sessions
+	revokeUserSessions(resetToken.UserID)
+
+	utils.RecordAuditAs(c, resetToken.UserID, "auth.password_reset", "user", resetToken.UserID, nil)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Password reset successfully, please log in again",
+	})
+}
+
+// ChangePassword - Logged-in user changes their password
+func ChangePassword(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	sessionID, _ := c.Get("session_id")
+
+	var req models.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user config.User
+	if err := config.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.CurrentPassword)); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Current password is incorrect"})
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Update("password", string(hashedPassword)).Error; err != nil {
+			return err
+		}
+		// A reset link asked for before the change must not override it
+		return tx.Model(&config.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", user.ID).
+			Update("used_at", time.Now()).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to change password"})
+		return
+	}
+
+	// Sign out every other device, keep the current session
+	config.DB.Model(&config.Session{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", user.ID, sessionID).
+		Update("revoked_at", time.Now())
+
+	utils.RecordAudit(c, "auth.password_change", "user", user.ID, nil)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Password changed successfully",
+	})
+}
@@ -0,0 +1,63 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestChangePasswordBurnsResetLinks(t *testing.T) {
+	r, mailer := setupServer(t)
+
+	register(t, r, "ada@example.com")
+	token := login(t, r, "ada@example.com")
+
+	doJSON(t, r, http.MethodPost, "/api/auth/forgot-password", "", map[string]string{"email": "ada@example.com"})
+	resetLink := mailedToken(t, mailer, "ada@example.com")
+
+	status, body := doJSON(t, r, http.MethodPut, "/api/profile/password", token, map[string]string{"current_password": "secret123", "new_password": "newsecret456"})
+	if status != http.StatusOK {
+		t.Fatalf("change password: got %d %v", status, body)
+	}
+
+	status, _ = doJSON(t, r, http.MethodPost, "/api/auth/reset-password", "", map[string]string{"token": resetLink, "new_password": "hijacked789"})
+	if status != http.StatusBadRequest {
+		t.Fatalf("old reset link: got %d, want 400", status)
+	}
+	status, _ = doJSON(t, r, http.MethodPost, "/api/auth/login", "", map[string]string{"email": "ada@example.com", "password": "newsecret456"})
+	if status != http.StatusOK {
+		t.Fatalf("login with the new password: got %d", status)
+	}
+}
+
+func TestForgotPasswordIsThrottled(t *testing.T) {
+	r, mailer := setupServer(t)
+
+	register(t, r, "ada@example.com")
+	forgot := func(email string) int {
+		status, _ := doJSON(t, r, http.MethodPost, "/api/auth/forgot-password", "", map[string]string{"email": email})
+		return status
+	}
+	for i := 0; i < 3; i++ {
+		if status := forgot("ada@example.com"); status != http.StatusOK {
+			t.Fatalf("request %d: got %d", i+1, status)
+		}
+	}
+	if status := forgot("ada@example.com"); status != http.StatusTooManyRequests {
+		t.Fatalf("fourth request: got %d, want 429", status)
+	}
+	if len(mailer.Sent) != 4 { // verification plus three reset links
+		t.Fatalf("expected 4 mails, got %d", len(mailer.Sent))
+	}
+
+	// Unknown addresses are limited the same way, and others aren't held up
+	for i := 0; i < 3; i++ {
+		forgot("nobody@example.com")
+	}
+	if status := forgot("nobody@example.com"); status != http.StatusTooManyRequests {
+		t.Fatalf("unknown address: got %d, want 429", status)
+	}
+	if status := forgot("bob@example.com"); status != http.StatusOK {
+		t.Fatalf("another address: got %d", status)
+	}
+	login(t, r, "ada@example.com")
+}
@@ -1,13 +1,31 @@
 package handlers
 
 import (
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"jobconnect-backend/config"
+	"jobconnect-backend/middleware"
 	"jobconnect-backend/models"
+	"jobconnect-backend/utils"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// notBannedOwner hides the work of banned users from public listings
+const notBannedOwner = "user_id NOT IN (SELECT id FROM users WHERE status = 'banned')"
+
+// Project statuses. Only published work is visible to anyone but the owner.
+const (
+	projectDraft     = "draft"
+	projectScheduled = "scheduled"
+	projectPublished = "published"
+	projectArchived  = "archived"
 )
 
 // GetProjects - Browse all projects (homepage)
@@ -18,11 +36,14 @@ func GetProjects(c *gin.Context) {
 
 	categorySlug := c.Query("category")
 	search := c.Query("search")
+	featured := c.Query("featured")
 
 	var projects []config.Project
 	var totalCount int64
 
-	query := config.DB.Preload("User").Preload("Category").Preload("Images").Where("deleted_at IS NULL")
+	query := config.DB.Preload("User").Preload("Category").Preload("Images").
+		Where("deleted_at IS NULL AND status = ?", projectPublished).
+		Where(notBannedOwner)
 
 	// Filter by category
 	if categorySlug != "" {
@@ -32,6 +53,11 @@ func GetProjects(c *gin.Context) {
 		}
 	}
 
+	// Featured only
+	if featured == "true" {
+		query = query.Where("featured = ?", true)
+	}
+
 	// Search
 	if search != "" {
 		query = query.Where("title ILIKE ? OR description ILIKE ? OR tags ILIKE ?",
@@ -42,7 +68,7 @@ func GetProjects(c *gin.Context) {
 	query.Model(&config.Project{}).Count(&totalCount)
 
 	// Get projects
-	query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&projects)
+	query.Offset(offset).Limit(limit).Order("publish_at DESC").Find(&projects)
 
 	// Build response
 	var response []models.ProjectResponse
@@ -76,6 +102,7 @@ func GetProjects(c *gin.Context) {
 			Tags:       project.Tags,
 			Views:      project.Views,
 			LikesCount: project.LikesCount,
+			Featured:   project.Featured,
 			CreatedAt:  project.CreatedAt,
 		})
 	}
@@ -98,13 +125,26 @@ func GetProject(c *gin.Context) {
 
 	var project config.Project
 	if err := config.DB.Preload("User").Preload("Category").Preload("Images").
-		Where("id = ? AND deleted_at IS NULL", projectID).First(&project).Error; err != nil {
+		Where("id = ? AND deleted_at IS NULL", projectID).Where(notBannedOwner).First(&project).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
 		return
 	}
 
-	// Increment views
-	config.DB.Model(&project).Update("views", project.Views+1)
+	// Unpublished work is only visible to its owner and moderators
+	canManage := middleware.CanActOn(c, project.UserID, utils.PermProjectUpdateAny)
+	if project.Status != projectPublished && !canManage {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+
+	// Increment views, counting only live work. Counters leave updated_at
+	// alone: it tracks the owner's own activity, e.g. for talent search.
+	views := project.Views
+	if project.Status == projectPublished {
+		config.DB.Model(&project).UpdateColumn("views", project.Views+1)
+		recordDailyView(project.ID)
+		views++
+	}
 
 	// Check if user liked this project
 	isLiked := false
@@ -125,21 +165,19 @@ func GetProject(c *gin.Context) {
 		})
 	}
 
+	// The owner's public profile; their email is never shown here
+	owner := userSummary(project.User)
+	owner.Bio = project.User.Bio
+	owner.Location = project.User.Location
+	owner.ForHire = project.User.ForHire
+
 	response := models.ProjectResponse{
 		ID:          project.ID,
 		Title:       project.Title,
 		Description: project.Description,
 		CoverImage:  project.CoverImage,
 		Images:      images,
-		User: models.UserResponse{
-			ID:        project.User.ID,
-			Name:      project.User.Name,
-			Email:     project.User.Email,
-			Bio:       project.User.Bio,
-			Location:  project.User.Location,
-			AvatarURL: project.User.AvatarURL,
-			ForHire:   project.User.ForHire,
-		},
+		User:        owner,
 		Category: models.CategoryResponse{
 			ID:   project.Category.ID,
 			Name: project.Category.Name,
@@ -147,11 +185,16 @@ func GetProject(c *gin.Context) {
 			Icon: project.Category.Icon,
 		},
 		Tags:       project.Tags,
-		Views:      project.Views + 1,
+		Views:      views,
 		LikesCount: project.LikesCount,
+		Featured:   project.Featured,
 		IsLiked:    isLiked,
+		PublishAt:  project.PublishAt,
 		CreatedAt:  project.CreatedAt,
 	}
+	if canManage {
+		response.Status = project.Status
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -159,7 +202,48 @@ func GetProject(c *gin.Context) {
 	})
 }
 
-// CreateProject - User uploads a project
+// publishable reports whether a project has what it needs to go live: a
+// description and at least one image
+func publishable(description string, images int) bool {
+	return strings.TrimSpace(description) != "" && images > 0
+}
+
+// projectPublishAt checks that a project can move to status and works out its
+// publish_at. Scheduling needs a time in the future, and a project can only be
+// scheduled or published once it has a description and at least one image.
+// current is the project's publish_at so far.
+func projectPublishAt(c *gin.Context, status string, requested, current *time.Time, description string, images int) (*time.Time, bool) {
+	if status == projectScheduled || status == projectPublished {
+		if !publishable(description, images) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A project needs a description and at least one image before it can be published"})
+			return nil, false
+		}
+	}
+
+	now := time.Now()
+	switch status {
+	case projectScheduled:
+		if requested == nil || !requested.After(now) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "publish_at must be in the future to schedule a project"})
+			return nil, false
+		}
+		return requested, true
+	case projectPublished, projectArchived:
+		// Work that already went live keeps its original date
+		if current != nil && !current.After(now) {
+			return current, true
+		}
+		if status == projectArchived {
+			return nil, true
+		}
+		return &now, true
+	default:
+		return nil, true
+	}
+}
+
+// CreateProject - User uploads a project, published right away (default),
+// scheduled for later or saved as a draft
 func CreateProject(c *gin.Context) {
 	userID, _ := c.Get("user_id")
 
@@ -169,8 +253,17 @@ func CreateProject(c *gin.Context) {
 		return
 	}
 
-	if len(req.ImageURLs) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "At least one image is required"})
+	if imageURL := foreignImage(userID.(uint), req.ImageURLs); imageURL != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Upload your images before adding them to a project: " + imageURL})
+		return
+	}
+
+	status := req.Status
+	if status == "" {
+		status = projectPublished
+	}
+	publishAt, ok := projectPublishAt(c, status, req.PublishAt, nil, req.Description, len(req.ImageURLs))
+	if !ok {
 		return
 	}
 
@@ -181,40 +274,46 @@ func CreateProject(c *gin.Context) {
 		UserID:      userID.(uint),
 		CategoryID:  req.CategoryID,
 		Tags:        req.Tags,
-		CoverImage:  req.ImageURLs[0], // First image is cover
+		Status:      status,
+		PublishAt:   publishAt,
 	}
 
-	if err := config.DB.Create(&project).Error; err != nil {
+	// Save it with its images (the first is the cover) as revision 1
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&project).Error; err != nil {
+			return err
+		}
+		if err := replaceProjectImages(tx, project.ID, req.ImageURLs); err != nil {
+			return err
+		}
+		return saveRevision(tx, project.ID, project.UserID, nil)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create project"})
 		return
 	}
 
-	// Add images
-	for i, imageURL := range req.ImageURLs {
-		projectImage := config.ProjectImage{
-			ProjectID: project.ID,
-			ImageURL:  imageURL,
-			Order:     i,
-		}
-		config.DB.Create(&projectImage)
-	}
-
 	c.JSON(http.StatusCreated, gin.H{
 		"success":    true,
 		"message":    "Project created successfully",
 		"project_id": project.ID,
+		"status":     project.Status,
 	})
 }
 
-// GetMyProjects - User gets their own projects
+// GetMyProjects - User gets their own projects in any status, filter with
+// ?status=
 func GetMyProjects(c *gin.Context) {
 	userID, _ := c.Get("user_id")
 
+	query := config.DB.Preload("Category").Preload("Images").
+		Where("user_id = ? AND deleted_at IS NULL", userID)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
 	var projects []config.Project
-	config.DB.Preload("Category").Preload("Images").
-		Where("user_id = ? AND deleted_at IS NULL", userID).
-		Order("created_at DESC").
-		Find(&projects)
+	query.Order("created_at DESC").Find(&projects)
 
 	var response []models.ProjectResponse
 	for _, project := range projects {
@@ -241,6 +340,8 @@ func GetMyProjects(c *gin.Context) {
 			Tags:       project.Tags,
 			Views:      project.Views,
 			LikesCount: project.LikesCount,
+			Status:     project.Status,
+			PublishAt:  project.PublishAt,
 			CreatedAt:  project.CreatedAt,
 		})
 	}
@@ -251,7 +352,8 @@ func GetMyProjects(c *gin.Context) {
 	})
 }
 
-// UpdateProject - User updates their project
+// UpdateProject - User updates their project (or anyone's with project.update.any).
+// Every edit is kept as a revision.
 func UpdateProject(c *gin.Context) {
 	userID, _ := c.Get("user_id")
 	projectID := c.Param("id")
@@ -264,11 +366,36 @@ func UpdateProject(c *gin.Context) {
 
 	// Check ownership
 	var project config.Project
-	if err := config.DB.Where("id = ? AND user_id = ?", projectID, userID).First(&project).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or unauthorized"})
+	if err := config.DB.Where("id = ? AND deleted_at IS NULL", projectID).First(&project).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+	if !middleware.CanActOn(c, project.UserID, utils.PermProjectUpdateAny) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only edit your own projects"})
 		return
 	}
 
+	// Live work has to keep at least one image
+	if req.ImageURLs != nil && len(*req.ImageURLs) == 0 && project.Status != projectDraft && project.Status != projectArchived {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A published or scheduled project needs at least one image"})
+		return
+	}
+
+	// New images have to be the owner's own uploads
+	if req.ImageURLs != nil {
+		known := projectImageHistory(project.ID)
+		added := []string{}
+		for _, imageURL := range *req.ImageURLs {
+			if !known[imageURL] {
+				added = append(added, imageURL)
+			}
+		}
+		if imageURL := foreignImage(project.UserID, added); imageURL != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Upload your images before adding them to a project: " + imageURL})
+			return
+		}
+	}
+
 	// Update
 	updates := make(map[string]interface{})
 	if req.Title != "" {
@@ -281,7 +408,36 @@ func UpdateProject(c *gin.Context) {
 		updates["tags"] = req.Tags
 	}
 
-	config.DB.Model(&project).Updates(updates)
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		// Lock the project before looking at its revisions, so concurrent
+		// edits are recorded one after the other
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("deleted_at IS NULL").First(&project, project.ID).Error; err != nil {
+			return err
+		}
+		if err := ensureBaselineRevision(tx, project); err != nil {
+			return err
+		}
+		if len(updates) > 0 {
+			if err := tx.Model(&project).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		if req.ImageURLs != nil {
+			if err := replaceProjectImages(tx, project.ID, *req.ImageURLs); err != nil {
+				return err
+			}
+		}
+		return saveRevision(tx, project.ID, userID.(uint), nil)
+	})
+	if err == gorm.ErrRecordNotFound {
+		// Taken down in the meantime
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update project"})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -289,24 +445,137 @@ func UpdateProject(c *gin.Context) {
 	})
 }
 
-// DeleteProject - User deletes their project
+// UpdateProjectStatus - Publish, schedule, archive or unpublish (back to
+// draft) a project
+func UpdateProjectStatus(c *gin.Context) {
+	projectID := c.Param("id")
+
+	var req models.ProjectStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var project config.Project
+	if err := config.DB.Where("id = ? AND deleted_at IS NULL", projectID).First(&project).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+	if !middleware.CanActOn(c, project.UserID, utils.PermProjectUpdateAny) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only edit your own projects"})
+		return
+	}
+
+	var images int64
+	config.DB.Model(&config.ProjectImage{}).Where("project_id = ?", project.ID).Count(&images)
+
+	publishAt, ok := projectPublishAt(c, req.Status, req.PublishAt, project.PublishAt, project.Description, int(images))
+	if !ok {
+		return
+	}
+
+	previous := project
+	if err := config.DB.Model(&project).Updates(map[string]interface{}{
+		"status":     req.Status,
+		"publish_at": publishAt,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update project"})
+		return
+	}
+
+	utils.RecordAudit(c, "project.status_change", "project", project.ID, utils.AuditDiff{
+		"status":     {From: previous.Status, To: req.Status},
+		"publish_at": {From: previous.PublishAt, To: publishAt},
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"message":    "Project " + req.Status,
+		"status":     req.Status,
+		"publish_at": publishAt,
+	})
+}
+
+// DeleteProject - User deletes their project (or anyone's with project.delete.any)
 func DeleteProject(c *gin.Context) {
-	userID, _ := c.Get("user_id")
 	projectID := c.Param("id")
 
-	result := config.DB.Where("id = ? AND user_id = ?", projectID, userID).Delete(&config.Project{})
+	// Work taken down by a moderator stays for them to review or restore
+	var project config.Project
+	if err := config.DB.Where("id = ?", projectID).First(&project).Error; err != nil ||
+		(project.DeletedAt != nil && !middleware.HasPermission(c, utils.PermProjectDeleteAny)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+	if !middleware.CanActOn(c, project.UserID, utils.PermProjectDeleteAny) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only delete your own projects"})
+		return
+	}
 
-	if result.RowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or unauthorized"})
+	if err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return purgeProject(tx, project.ID)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete project"})
 		return
 	}
 
+	utils.RecordAudit(c, "project.delete", "project", project.ID, utils.AuditDiff{
+		"title":    {From: project.Title, To: nil},
+		"owner_id": {From: project.UserID, To: nil},
+	})
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Project deleted successfully",
 	})
 }
 
+// purgeProject permanently removes a project together with the rows that
+// reference it
+func purgeProject(tx *gorm.DB, projectID uint) error {
+	for _, model := range []interface{}{&config.ProjectImage{}, &config.Like{}, &config.Comment{}, &config.ProjectDailyView{}, &config.GigApplicationProject{}, &config.ProjectRevision{}} {
+		if err := tx.Where("project_id = ?", projectID).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Where("id = ?", projectID).Delete(&config.Project{}).Error
+}
+
+// RunProjectPublisher publishes scheduled projects once their publish_at has
+// passed, checking every interval. It blocks, so start it in its own goroutine.
+func RunProjectPublisher(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		var projects []config.Project
+		config.DB.Where("status = ? AND publish_at <= ? AND deleted_at IS NULL", projectScheduled, time.Now()).Find(&projects)
+		for _, project := range projects {
+			// Leave it alone if the owner changed it in the meantime
+			result := config.DB.Model(&config.Project{}).
+				Where("id = ? AND status = ?", project.ID, projectScheduled).
+				Update("status", projectPublished)
+			if result.Error != nil {
+				log.Printf("Warning: Failed to publish project %d: %v", project.ID, result.Error)
+				continue
+			}
+			if result.RowsAffected == 0 {
+				continue
+			}
+			utils.RecordSystemAudit("project.publish", "project", project.ID, utils.AuditDiff{
+				"status": {From: projectScheduled, To: projectPublished},
+			})
+		}
+		<-ticker.C
+	}
+}
+
+// recordDailyView bumps today's view counter for the analytics dashboard
+func recordDailyView(projectID uint) {
+	config.DB.Exec(`INSERT INTO project_daily_views (project_id, day, views) VALUES (?, CURRENT_DATE, 1)
+		ON CONFLICT (project_id, day) DO UPDATE SET views = project_daily_views.views + 1`, projectID)
+}
+
 // GetCategories - Get all categories
 func GetCategories(c *gin.Context) {
 	var categories []config.Category
@@ -0,0 +1,238 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"jobconnect-backend/config"
+	"jobconnect-backend/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// creative registers a verified creative and returns their ID and token
+func creative(t *testing.T, r http.Handler, email string) (uint, string) {
+	t.Helper()
+
+	userID := register(t, r, email)
+	config.DB.Model(&config.User{}).Where("id = ?", userID).Update("verified", true)
+	return userID, login(t, r, email)
+}
+
+func uploaded(t *testing.T, userID uint, name string) string {
+	t.Helper()
+
+	imageURL := "https://res.cloudinary.com/test/image/upload/v1/rosyartgrid/projects/" + name + ".jpg"
+	if err := config.DB.Create(&config.UploadedImage{UserID: userID, PublicID: "rosyartgrid/projects/" + name, ImageURL: imageURL}).Error; err != nil {
+		t.Fatal(err)
+	}
+	return imageURL
+}
+
+func TestCreateProjectOnlyTakesOwnUploads(t *testing.T) {
+	r, _ := setupServer(t)
+
+	category := config.Category{Name: "Illustration", Slug: "illustration"}
+	config.DB.Create(&category)
+
+	adaID, adaToken := creative(t, r, "ada@example.com")
+	_, eveToken := creative(t, r, "eve@example.com")
+	adaImage := uploaded(t, adaID, "ada-1")
+
+	project := map[string]interface{}{
+		"title":       "Borrowed",
+		"description": "Not mine",
+		"category_id": category.ID,
+		"image_urls":  []string{adaImage},
+	}
+	status, body := doJSON(t, r, http.MethodPost, "/api/projects", eveToken, project)
+	if status != http.StatusBadRequest {
+		t.Fatalf("someone else's image: got %d %v", status, body)
+	}
+	project["image_urls"] = []string{"https://example.com/hotlinked.jpg"}
+	status, body = doJSON(t, r, http.MethodPost, "/api/projects", eveToken, project)
+	if status != http.StatusBadRequest {
+		t.Fatalf("image we never uploaded: got %d %v", status, body)
+	}
+
+	project["image_urls"] = []string{adaImage}
+	status, body = doJSON(t, r, http.MethodPost, "/api/projects", adaToken, project)
+	if status != http.StatusCreated {
+		t.Fatalf("own image: got %d %v", status, body)
+	}
+
+	var projects int64
+	config.DB.Model(&config.Project{}).Count(&projects)
+	if projects != 1 {
+		t.Fatalf("expected 1 project, got %d", projects)
+	}
+}
+
+func TestPublicPagesIgnoreBadCredentials(t *testing.T) {
+	r, _ := setupServer(t)
+
+	category := config.Category{Name: "Illustration", Slug: "illustration"}
+	config.DB.Create(&category)
+
+	adaID, adaToken := creative(t, r, "ada@example.com")
+	status, body := doJSON(t, r, http.MethodPost, "/api/projects", adaToken, map[string]interface{}{
+		"title":       "Public",
+		"description": "For everyone",
+		"category_id": category.ID,
+		"image_urls":  []string{uploaded(t, adaID, "ada-1")},
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("create project: got %d %v", status, body)
+	}
+	projectID := uint(body["project_id"].(float64))
+
+	expired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, utils.Claims{
+		UserID: adaID,
+		Email:  "ada@example.com",
+		Role:   "creative",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    utils.Issuer(),
+			Audience:  jwt.ClaimStrings{utils.AudienceAccess},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+		},
+	}).SignedString([]byte("test-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A key for writing projects doesn't grant reading profiles
+	apiKey, prefix, err := utils.GenerateAPIKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.DB.Create(&config.APIKey{UserID: adaID, Name: "deploy", Prefix: prefix, KeyHash: utils.HashToken(apiKey), Scopes: utils.ScopeProjectsWrite})
+
+	for name, token := range map[string]string{"expired token": expired, "scope-less API key": apiKey, "garbage": "not-a-token"} {
+		for _, path := range []string{fmt.Sprintf("/api/projects/%d", projectID), fmt.Sprintf("/api/users/%d", adaID)} {
+			status, body := doJSON(t, r, http.MethodGet, path, token, nil)
+			if status != http.StatusOK {
+				t.Errorf("%s on %s: got %d %v", name, path, status, body)
+			}
+		}
+	}
+}
+
+func TestUpdateProjectOnlyTakesOwnUploads(t *testing.T) {
+	r, _ := setupServer(t)
+
+	category := config.Category{Name: "Illustration", Slug: "illustration"}
+	config.DB.Create(&category)
+
+	adaID, adaToken := creative(t, r, "ada@example.com")
+	eveID, _ := creative(t, r, "eve@example.com")
+	first, second := uploaded(t, adaID, "ada-1"), uploaded(t, adaID, "ada-2")
+	eveImage := uploaded(t, eveID, "eve-1")
+
+	status, body := doJSON(t, r, http.MethodPost, "/api/projects", adaToken, map[string]interface{}{
+		"title":       "Mine",
+		"description": "All mine",
+		"category_id": category.ID,
+		"image_urls":  []string{first},
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("create project: got %d %v", status, body)
+	}
+	path := fmt.Sprintf("/api/projects/%d", uint(body["project_id"].(float64)))
+
+	update := func(imageURLs ...string) int {
+		status, _ := doJSON(t, r, http.MethodPut, path, adaToken, map[string]interface{}{"image_urls": imageURLs})
+		return status
+	}
+	if status := update(first, eveImage); status != http.StatusBadRequest {
+		t.Fatalf("someone else's image: got %d, want 400", status)
+	}
+	if status := update(first, "https://example.com/hotlinked.jpg"); status != http.StatusBadRequest {
+		t.Fatalf("image we never uploaded: got %d, want 400", status)
+	}
+	if status := update(second); status != http.StatusOK {
+		t.Fatalf("own upload: got %d, want 200", status)
+	}
+
+	// Images from earlier revisions can come back, even once their upload
+	// record is gone
+	config.DB.Where("image_url = ?", first).Delete(&config.UploadedImage{})
+	if status := update(second, first); status != http.StatusOK {
+		t.Fatalf("image from an earlier revision: got %d, want 200", status)
+	}
+}
+
+func TestViewsAndLikesKeepUpdatedAt(t *testing.T) {
+	r, _ := setupServer(t)
+
+	category := config.Category{Name: "Illustration", Slug: "illustration"}
+	config.DB.Create(&category)
+
+	adaID, adaToken := creative(t, r, "ada@example.com")
+	_, eveToken := creative(t, r, "eve@example.com")
+	status, body := doJSON(t, r, http.MethodPost, "/api/projects", adaToken, map[string]interface{}{
+		"title":       "Quiet",
+		"description": "Untouched since",
+		"category_id": category.ID,
+		"image_urls":  []string{uploaded(t, adaID, "ada-1")},
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("create project: got %d %v", status, body)
+	}
+	projectID := uint(body["project_id"].(float64))
+
+	lastEdit := time.Now().Add(-30 * 24 * time.Hour).Truncate(time.Second)
+	config.DB.Model(&config.Project{}).Where("id = ?", projectID).UpdateColumn("updated_at", lastEdit)
+
+	doJSON(t, r, http.MethodGet, fmt.Sprintf("/api/projects/%d", projectID), "", nil)
+	doJSON(t, r, http.MethodPost, fmt.Sprintf("/api/projects/%d/like", projectID), eveToken, nil)
+
+	var project config.Project
+	config.DB.First(&project, projectID)
+	if project.Views != 1 || project.LikesCount != 1 {
+		t.Fatalf("counters not updated: %d views, %d likes", project.Views, project.LikesCount)
+	}
+	if !project.UpdatedAt.Equal(lastEdit) {
+		t.Fatalf("updated_at moved from %v to %v", lastEdit, project.UpdatedAt)
+	}
+}
+
+func TestProjectStatusChangeIsAudited(t *testing.T) {
+	r, _ := setupServer(t)
+
+	adaID, adaToken := creative(t, r, "ada@example.com")
+	projectID := publishedProject(t, r, adaID, adaToken)
+
+	status, body := doJSON(t, r, http.MethodPut, fmt.Sprintf("/api/projects/%d/status", projectID), adaToken, map[string]string{"status": "archived"})
+	if status != http.StatusOK {
+		t.Fatalf("archive: got %d %v", status, body)
+	}
+
+	var event config.AuditEvent
+	if err := config.DB.Where("action = ?", "project.status_change").First(&event).Error; err != nil {
+		t.Fatal("status change not audited:", err)
+	}
+	if !strings.Contains(event.Changes, `"status":{"from":"published","to":"archived"}`) || !strings.Contains(event.Changes, `"publish_at"`) {
+		t.Fatalf("unexpected audit diff: %s", event.Changes)
+	}
+}
+
+func TestProjectPageHidesOwnerEmail(t *testing.T) {
+	r, _ := setupServer(t)
+
+	adaID, adaToken := creative(t, r, "ada@example.com")
+	projectID := publishedProject(t, r, adaID, adaToken)
+
+	for name, token := range map[string]string{"anonymous": "", "owner": adaToken} {
+		status, body := doJSON(t, r, http.MethodGet, fmt.Sprintf("/api/projects/%d", projectID), token, nil)
+		if status != http.StatusOK {
+			t.Fatalf("%s: got %d %v", name, status, body)
+		}
+		owner := body["project"].(map[string]interface{})["user"].(map[string]interface{})
+		if owner["email"] != "" {
+			t.Errorf("%s sees the owner's email: %v", name, owner["email"])
+		}
+	}
+}
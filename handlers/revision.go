@@ -0,0 +1,343 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"jobconnect-backend/config"
+	"jobconnect-backend/middleware"
+	"jobconnect-backend/models"
+	"jobconnect-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// nextRevision builds, without saving it, the project's next revision from its
+// current content. The project row stays locked until the transaction ends, so
+// concurrent edits get consecutive numbers.
+func nextRevision(tx *gorm.DB, projectID uint) (config.ProjectRevision, error) {
+	var project config.Project
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Images", func(db *gorm.DB) *gorm.DB {
+			return db.Order(`project_images."order"`)
+		}).
+		First(&project, projectID).Error; err != nil {
+		return config.ProjectRevision{}, err
+	}
+
+	imageURLs := []string{}
+	for _, img := range project.Images {
+		imageURLs = append(imageURLs, img.ImageURL)
+	}
+	encoded, err := json.Marshal(imageURLs)
+	if err != nil {
+		return config.ProjectRevision{}, err
+	}
+
+	var last int
+	if err := tx.Model(&config.ProjectRevision{}).Where("project_id = ?", projectID).
+		Select("COALESCE(MAX(number), 0)").Scan(&last).Error; err != nil {
+		return config.ProjectRevision{}, err
+	}
+
+	return config.ProjectRevision{
+		ProjectID:   projectID,
+		Number:      last + 1,
+		Title:       project.Title,
+		Description: project.Description,
+		Tags:        project.Tags,
+		ImageURLs:   string(encoded),
+	}, nil
+}
+
+// ensureBaselineRevision records the content of a project from before
+// revisions were kept as its first revision, so its first edit can be undone.
+// The caller must already hold the project row lock, or two first edits could
+// both find no revisions and record the baseline twice.
+func ensureBaselineRevision(tx *gorm.DB, project config.Project) error {
+	var count int64
+	if err := tx.Model(&config.ProjectRevision{}).Where("project_id = ?", project.ID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	revision, err := nextRevision(tx, project.ID)
+	if err != nil {
+		return err
+	}
+	revision.AuthorID = project.UserID
+	revision.CreatedAt = project.UpdatedAt
+	return tx.Create(&revision).Error
+}
+
+// saveRevision records the project's current content as a new revision by
+// authorID. Nothing is recorded when the content is the same as the latest
+// revision.
+func saveRevision(tx *gorm.DB, projectID, authorID uint, restoredFrom *int) error {
+	revision, err := nextRevision(tx, projectID)
+	if err != nil {
+		return err
+	}
+
+	var latest config.ProjectRevision
+	if err := tx.Where("project_id = ?", projectID).Order("number DESC").First(&latest).Error; err == nil {
+		if len(revisionChanges(latest, revision)) == 0 {
+			return nil
+		}
+	}
+
+	revision.AuthorID = authorID
+	revision.RestoredFrom = restoredFrom
+	return tx.Create(&revision).Error
+}
+
+// replaceProjectImages swaps the project's images for imageURLs, in order,
+// and makes the first one the cover
+func replaceProjectImages(tx *gorm.DB, projectID uint, imageURLs []string) error {
+	if err := tx.Where("project_id = ?", projectID).Delete(&config.ProjectImage{}).Error; err != nil {
+		return err
+	}
+	for i, imageURL := range imageURLs {
+		if err := tx.Create(&config.ProjectImage{ProjectID: projectID, ImageURL: imageURL, Order: i}).Error; err != nil {
+			return err
+		}
+	}
+
+	coverImage := ""
+	if len(imageURLs) > 0 {
+		coverImage = imageURLs[0]
+	}
+	return tx.Model(&config.Project{}).Where("id = ?", projectID).Update("cover_image", coverImage).Error
+}
+
+// projectImageHistory is every image the project shows or showed in one of
+// its revisions
+func projectImageHistory(projectID uint) map[string]bool {
+	known := make(map[string]bool)
+
+	var current []string
+	config.DB.Model(&config.ProjectImage{}).Where("project_id = ?", projectID).Pluck("image_url", &current)
+	for _, imageURL := range current {
+		known[imageURL] = true
+	}
+
+	var revisions []config.ProjectRevision
+	config.DB.Select("image_urls").Where("project_id = ?", projectID).Find(&revisions)
+	for _, revision := range revisions {
+		for _, imageURL := range revisionImages(revision) {
+			known[imageURL] = true
+		}
+	}
+	return known
+}
+
+func revisionImages(revision config.ProjectRevision) []string {
+	imageURLs := []string{}
+	json.Unmarshal([]byte(revision.ImageURLs), &imageURLs)
+	return imageURLs
+}
+
+// revisionChanges compares two revisions field by field
+func revisionChanges(from, to config.ProjectRevision) map[string]models.RevisionChange {
+	changes := make(map[string]models.RevisionChange)
+	if from.Title != to.Title {
+		changes["title"] = models.RevisionChange{From: from.Title, To: to.Title}
+	}
+	if from.Description != to.Description {
+		changes["description"] = models.RevisionChange{From: from.Description, To: to.Description}
+	}
+	if from.Tags != to.Tags {
+		changes["tags"] = models.RevisionChange{From: from.Tags, To: to.Tags}
+	}
+
+	fromImages, toImages := revisionImages(from), revisionImages(to)
+	if !sameStrings(fromImages, toImages) {
+		change := models.RevisionChange{From: fromImages, To: toImages}
+		before := make(map[string]bool)
+		for _, url := range fromImages {
+			before[url] = true
+		}
+		after := make(map[string]bool)
+		for _, url := range toImages {
+			after[url] = true
+			if !before[url] {
+				change.Added = append(change.Added, url)
+			}
+		}
+		for _, url := range fromImages {
+			if !after[url] {
+				change.Removed = append(change.Removed, url)
+			}
+		}
+		changes["images"] = change
+	}
+	return changes
+}
+
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// revisionProject loads a project whose history the current user may see:
+// their own, or anyone's with project.update.any
+func revisionProject(c *gin.Context) (config.Project, bool) {
+	var project config.Project
+	if err := config.DB.Where("id = ? AND deleted_at IS NULL", c.Param("id")).First(&project).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return project, false
+	}
+	if !middleware.CanActOn(c, project.UserID, utils.PermProjectUpdateAny) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only view the history of your own projects"})
+		return project, false
+	}
+	return project, true
+}
+
+// GetProjectRevisions - A project's revisions, newest first, with the fields
+// each one changed
+func GetProjectRevisions(c *gin.Context) {
+	project, ok := revisionProject(c)
+	if !ok {
+		return
+	}
+
+	var revisions []config.ProjectRevision
+	config.DB.Preload("Author").Where("project_id = ?", project.ID).Order("number").Find(&revisions)
+
+	response := []models.RevisionResponse{}
+	for i := len(revisions) - 1; i >= 0; i-- {
+		changed := []string{}
+		if i > 0 {
+			for field := range revisionChanges(revisions[i-1], revisions[i]) {
+				changed = append(changed, field)
+			}
+			sort.Strings(changed)
+		}
+		response = append(response, models.RevisionResponse{
+			Number:       revisions[i].Number,
+			Author:       userSummary(revisions[i].Author),
+			Changed:      changed,
+			RestoredFrom: revisions[i].RestoredFrom,
+			CreatedAt:    revisions[i].CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"revisions": response,
+	})
+}
+
+// GetProjectRevisionDiff - Compare two revisions with ?from=&to=. to defaults
+// to the latest revision and from to the one before to.
+func GetProjectRevisionDiff(c *gin.Context) {
+	project, ok := revisionProject(c)
+	if !ok {
+		return
+	}
+
+	to, err := strconv.Atoi(c.Query("to"))
+	if err != nil {
+		var latest config.ProjectRevision
+		if err := config.DB.Where("project_id = ?", project.ID).Order("number DESC").First(&latest).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "This project has no revisions yet"})
+			return
+		}
+		to = latest.Number
+	}
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil {
+		from = to - 1
+	}
+
+	var revisions []config.ProjectRevision
+	config.DB.Where("project_id = ? AND number IN ?", project.ID, []int{from, to}).Find(&revisions)
+	found := make(map[int]config.ProjectRevision)
+	for _, revision := range revisions {
+		found[revision.Number] = revision
+	}
+	fromRevision, fromOK := found[from]
+	toRevision, toOK := found[to]
+	if !fromOK || !toOK {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revision not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"diff": models.RevisionDiffResponse{
+			From:    from,
+			To:      to,
+			Changes: revisionChanges(fromRevision, toRevision),
+		},
+	})
+}
+
+// RollbackProject - Owner restores a project to an earlier revision. The
+// rollback is recorded as a new revision, so it can be undone too.
+func RollbackProject(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var project config.Project
+	if err := config.DB.Where("id = ? AND deleted_at IS NULL", c.Param("id")).First(&project).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+	if project.UserID != userID.(uint) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the owner can roll back a project"})
+		return
+	}
+
+	var revision config.ProjectRevision
+	if err := config.DB.Where("project_id = ? AND number = ?", project.ID, c.Param("number")).First(&revision).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revision not found"})
+		return
+	}
+
+	imageURLs := revisionImages(revision)
+	if project.Status != projectDraft && project.Status != projectArchived && !publishable(revision.Description, len(imageURLs)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "That revision has no description or images; move the project back to draft before restoring it"})
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&project).Updates(map[string]interface{}{
+			"title":       revision.Title,
+			"description": revision.Description,
+			"tags":        revision.Tags,
+		}).Error; err != nil {
+			return err
+		}
+		if err := replaceProjectImages(tx, project.ID, imageURLs); err != nil {
+			return err
+		}
+		return saveRevision(tx, project.ID, userID.(uint), &revision.Number)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to roll back project"})
+		return
+	}
+
+	utils.RecordAudit(c, "project.rollback", "project", project.ID, utils.AuditDiff{
+		"revision": {From: nil, To: revision.Number},
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Project restored to revision " + strconv.Itoa(revision.Number),
+	})
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"jobconnect-backend/config"
+	"jobconnect-backend/models"
+	"jobconnect-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// issueTokens starts a new session for the user and returns the access token
+// and the raw refresh token. The refresh token is only ever returned here.
+func issueTokens(c *gin.Context, user config.User) (string, string, error) {
+	refreshToken, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	now := time.Now()
+	session := config.Session{
+		UserID:           user.ID,
+		RefreshTokenHash: utils.HashToken(refreshToken),
+		UserAgent:        c.Request.UserAgent(),
+		IPAddress:        c.ClientIP(),
+		ExpiresAt:        now.Add(utils.RefreshTokenTTL),
+		LastUsedAt:       now,
+	}
+	if err := config.DB.Create(&session).Error; err != nil {
+		return "", "", err
+	}
+
+	accessToken, err := utils.GenerateToken(user.ID, user.Email, user.Role, session.ID)
+	if err != nil {
+		return "", "", err
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+// revokeUserSessions revokes every active session of a user
+func revokeUserSessions(userID interface{}) error {
+	return config.DB.Model(&config.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RefreshToken - Exchange a refresh token for a new access/refresh token pair
+func RefreshToken(c *gin.Context) {
+	var req models.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokenHash := utils.HashToken(req.RefreshToken)
+	now := time.Now()
+
+	var session config.Session
+	if err := config.DB.Preload("User").Where("refresh_token_hash = ?", tokenHash).First(&session).Error; err != nil {
+		// A rotated-out token being replayed means it leaked; kill the session
+		if err := config.DB.Where("previous_token_hash = ?", tokenHash).First(&session).Error; err == nil {
+			config.DB.Model(&session).Update("revoked_at", now)
+		}
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+
+	if session.RevokedAt != nil || now.After(session.ExpiresAt) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session expired or revoked"})
+		return
+	}
+
+	// Rotate the refresh token
+	newRefreshToken, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	result := config.DB.Model(&config.Session{}).
+		Where("id = ? AND refresh_token_hash = ?", session.ID, tokenHash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash":  utils.HashToken(newRefreshToken),
+			"previous_token_hash": tokenHash,
+			"last_used_at":        now,
+			"expires_at":          now.Add(utils.RefreshTokenTTL),
+			"ip_address":          c.ClientIP(),
+		})
+	if result.Error != nil || result.RowsAffected == 0 {
+		// Lost a race against a concurrent refresh with the same token
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+
+	accessToken, err := utils.GenerateToken(session.User.ID, session.User.Email, session.User.Role, session.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":       true,
+		"token":         accessToken,
+		"refresh_token": newRefreshToken,
+		"expires_in":    int(utils.AccessTokenTTL.Seconds()),
+	})
+}
+
+// Logout - Revoke the session belonging to a refresh token
+func Logout(c *gin.Context) {
+	var req models.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	config.DB.Model(&config.Session{}).
+		Where("refresh_token_hash = ? AND revoked_at IS NULL", utils.HashToken(req.RefreshToken)).
+		Update("revoked_at", time.Now())
+
+	// Always succeed so the endpoint can't be used to probe tokens
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Logged out",
+	})
+}
+
+// LogoutAll - Revoke every session of the current user ("log out all devices")
+func LogoutAll(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	if err := revokeUserSessions(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Logged out of all devices",
+	})
+}
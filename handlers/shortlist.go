@@ -0,0 +1,379 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"jobconnect-backend/config"
+	"jobconnect-backend/models"
+	"jobconnect-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// companyShortlist loads a shortlist of the current user's company, answering
+// 404 if it belongs to another company. Shortlists are only ever served
+// through these company-scoped endpoints, so candidates never see them.
+func companyShortlist(c *gin.Context) (config.User, config.Shortlist, bool) {
+	var shortlist config.Shortlist
+
+	user, ok := currentCompanyUser(c)
+	if !ok {
+		return user, shortlist, false
+	}
+
+	if err := config.DB.Where("id = ? AND company_id = ?", c.Param("id"), *user.CompanyID).First(&shortlist).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Shortlist not found"})
+		return user, shortlist, false
+	}
+	return user, shortlist, true
+}
+
+// GetShortlists - List the company's shortlists
+func GetShortlists(c *gin.Context) {
+	user, ok := currentCompanyUser(c)
+	if !ok {
+		return
+	}
+
+	var shortlists []config.Shortlist
+	config.DB.Preload("CreatedBy").Preload("Entries").
+		Where("company_id = ?", *user.CompanyID).
+		Order("updated_at DESC").
+		Find(&shortlists)
+
+	response := []models.ShortlistResponse{}
+	for _, shortlist := range shortlists {
+		response = append(response, models.ShortlistResponse{
+			ID:              shortlist.ID,
+			Name:            shortlist.Name,
+			Description:     shortlist.Description,
+			CreatedBy:       userSummary(shortlist.CreatedBy),
+			CandidatesCount: len(shortlist.Entries),
+			CreatedAt:       shortlist.CreatedAt,
+			UpdatedAt:       shortlist.UpdatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"shortlists": response,
+	})
+}
+
+// CreateShortlist - Start a new shortlist for the company
+func CreateShortlist(c *gin.Context) {
+	var req models.ShortlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, ok := currentCompanyUser(c)
+	if !ok {
+		return
+	}
+
+	shortlist := config.Shortlist{
+		CompanyID:   *user.CompanyID,
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		CreatedByID: user.ID,
+	}
+	if err := config.DB.Create(&shortlist).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create shortlist"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":      true,
+		"message":      "Shortlist created",
+		"shortlist_id": shortlist.ID,
+	})
+}
+
+// GetShortlist - A shortlist with its candidates and notes
+func GetShortlist(c *gin.Context) {
+	_, shortlist, ok := companyShortlist(c)
+	if !ok {
+		return
+	}
+
+	config.DB.Preload("CreatedBy").
+		Preload("Entries", func(db *gorm.DB) *gorm.DB {
+			return db.Order("shortlist_entries.created_at")
+		}).
+		Preload("Entries.Candidate").
+		Preload("Entries.AddedBy").
+		Preload("Entries.Notes", func(db *gorm.DB) *gorm.DB {
+			return db.Order("shortlist_notes.created_at")
+		}).
+		Preload("Entries.Notes.Author").
+		First(&shortlist, shortlist.ID)
+
+	response := models.ShortlistResponse{
+		ID:              shortlist.ID,
+		Name:            shortlist.Name,
+		Description:     shortlist.Description,
+		CreatedBy:       userSummary(shortlist.CreatedBy),
+		CandidatesCount: len(shortlist.Entries),
+		Candidates:      []models.ShortlistCandidateResponse{},
+		CreatedAt:       shortlist.CreatedAt,
+		UpdatedAt:       shortlist.UpdatedAt,
+	}
+	for _, entry := range shortlist.Entries {
+		notes := []models.ShortlistNoteResponse{}
+		for _, note := range entry.Notes {
+			notes = append(notes, models.ShortlistNoteResponse{
+				ID:        note.ID,
+				Author:    userSummary(note.Author),
+				Content:   note.Content,
+				CreatedAt: note.CreatedAt,
+			})
+		}
+
+		candidate := userSummary(entry.Candidate)
+		candidate.Location = entry.Candidate.Location
+		candidate.ForHire = entry.Candidate.ForHire
+		response.Candidates = append(response.Candidates, models.ShortlistCandidateResponse{
+			Candidate: candidate,
+			Status:    entry.Status,
+			AddedBy:   userSummary(entry.AddedBy),
+			Notes:     notes,
+			CreatedAt: entry.CreatedAt,
+			UpdatedAt: entry.UpdatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"shortlist": response,
+	})
+}
+
+// UpdateShortlist - Rename a shortlist or change its description
+func UpdateShortlist(c *gin.Context) {
+	var req models.ShortlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	_, shortlist, ok := companyShortlist(c)
+	if !ok {
+		return
+	}
+
+	config.DB.Model(&shortlist).Updates(map[string]interface{}{
+		"name":        strings.TrimSpace(req.Name),
+		"description": req.Description,
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Shortlist updated",
+	})
+}
+
+// DeleteShortlist - Delete a shortlist with its entries and notes
+func DeleteShortlist(c *gin.Context) {
+	_, shortlist, ok := companyShortlist(c)
+	if !ok {
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("entry_id IN (SELECT id FROM shortlist_entries WHERE shortlist_id = ?)", shortlist.ID).
+			Delete(&config.ShortlistNote{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("shortlist_id = ?", shortlist.ID).Delete(&config.ShortlistEntry{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&shortlist).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete shortlist"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Shortlist deleted",
+	})
+}
+
+// AddShortlistCandidate - Put a creative on a shortlist, optionally with a
+// first note
+func AddShortlistCandidate(c *gin.Context) {
+	var req models.AddShortlistCandidateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, shortlist, ok := companyShortlist(c)
+	if !ok {
+		return
+	}
+
+	var candidate config.User
+	if err := config.DB.Where("id = ? AND role = ? AND status NOT IN ?", req.UserID, "creative", []string{utils.StatusBanned, utils.StatusDeleted}).
+		First(&candidate).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Creative not found"})
+		return
+	}
+
+	var count int64
+	config.DB.Model(&config.ShortlistEntry{}).Where("shortlist_id = ? AND candidate_id = ?", shortlist.ID, candidate.ID).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Candidate is already on this shortlist"})
+		return
+	}
+
+	status := req.Status
+	if status == "" {
+		status = "new"
+	}
+
+	entry := config.ShortlistEntry{
+		ShortlistID: shortlist.ID,
+		CandidateID: candidate.ID,
+		Status:      status,
+		AddedByID:   user.ID,
+	}
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&entry).Error; err != nil {
+			return err
+		}
+		if note := strings.TrimSpace(req.Note); note != "" {
+			if err := tx.Create(&config.ShortlistNote{EntryID: entry.ID, AuthorID: user.ID, Content: note}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&shortlist).Update("updated_at", entry.CreatedAt).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add candidate"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Candidate added to shortlist",
+	})
+}
+
+// shortlistEntry loads a candidate's entry on a company shortlist
+func shortlistEntry(c *gin.Context) (config.User, config.ShortlistEntry, bool) {
+	var entry config.ShortlistEntry
+
+	user, shortlist, ok := companyShortlist(c)
+	if !ok {
+		return user, entry, false
+	}
+
+	if err := config.DB.Where("shortlist_id = ? AND candidate_id = ?", shortlist.ID, c.Param("userId")).First(&entry).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Candidate is not on this shortlist"})
+		return user, entry, false
+	}
+	return user, entry, true
+}
+
+// UpdateShortlistCandidate - Move a candidate to another status
+func UpdateShortlistCandidate(c *gin.Context) {
+	var req models.ShortlistStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	_, entry, ok := shortlistEntry(c)
+	if !ok {
+		return
+	}
+
+	config.DB.Model(&entry).Update("status", req.Status)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Candidate status updated",
+		"status":  req.Status,
+	})
+}
+
+// RemoveShortlistCandidate - Take a candidate off a shortlist, with their notes
+func RemoveShortlistCandidate(c *gin.Context) {
+	_, entry, ok := shortlistEntry(c)
+	if !ok {
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("entry_id = ?", entry.ID).Delete(&config.ShortlistNote{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entry).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove candidate"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Candidate removed from shortlist",
+	})
+}
+
+// AddShortlistNote - Add a private note about a shortlisted candidate
+func AddShortlistNote(c *gin.Context) {
+	var req models.ShortlistNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, entry, ok := shortlistEntry(c)
+	if !ok {
+		return
+	}
+
+	note := config.ShortlistNote{
+		EntryID:  entry.ID,
+		AuthorID: user.ID,
+		Content:  strings.TrimSpace(req.Content),
+	}
+	if err := config.DB.Create(&note).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add note"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Note added",
+		"note_id": note.ID,
+	})
+}
+
+// DeleteShortlistNote - Delete a note; only its author can
+func DeleteShortlistNote(c *gin.Context) {
+	user, shortlist, ok := companyShortlist(c)
+	if !ok {
+		return
+	}
+
+	result := config.DB.
+		Where("id = ? AND author_id = ? AND entry_id IN (SELECT id FROM shortlist_entries WHERE shortlist_id = ?)", c.Param("noteId"), user.ID, shortlist.ID).
+		Delete(&config.ShortlistNote{})
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Note not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Note deleted",
+	})
+}
@@ -5,7 +5,9 @@ import (
 	"strconv"
 
 	"jobconnect-backend/config"
+	"jobconnect-backend/middleware"
 	"jobconnect-backend/models"
+	"jobconnect-backend/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,9 +17,9 @@ func LikeProject(c *gin.Context) {
 	userID, _ := c.Get("user_id")
 	projectID := c.Param("id")
 
-	// Check if project exists
+	// Check if project exists and is live
 	var project config.Project
-	if err := config.DB.Where("id = ?", projectID).First(&project).Error; err != nil {
+	if err := config.DB.Where("id = ? AND status = ?", projectID, projectPublished).First(&project).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
 		return
 	}
@@ -41,7 +43,7 @@ func LikeProject(c *gin.Context) {
 	}
 
 	// Increment likes count
-	config.DB.Model(&project).Update("likes_count", project.LikesCount+1)
+	config.DB.Model(&project).UpdateColumn("likes_count", project.LikesCount+1)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -67,7 +69,7 @@ func UnlikeProject(c *gin.Context) {
 	var project config.Project
 	if err := config.DB.Where("id = ?", projectID).First(&project).Error; err == nil {
 		if project.LikesCount > 0 {
-			config.DB.Model(&project).Update("likes_count", project.LikesCount-1)
+			config.DB.Model(&project).UpdateColumn("likes_count", project.LikesCount-1)
 		}
 	}
 
@@ -114,9 +116,9 @@ func AddComment(c *gin.Context) {
 		return
 	}
 
-	// Check if project exists
+	// Check if project exists and is live
 	var project config.Project
-	if err := config.DB.Where("id = ?", projectID).First(&project).Error; err != nil {
+	if err := config.DB.Where("id = ? AND status = ?", projectID, projectPublished).First(&project).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
 		return
 	}
@@ -145,7 +147,7 @@ func GetProjectComments(c *gin.Context) {
 	projectID := c.Param("id")
 
 	var comments []config.Comment
-	config.DB.Preload("User").Where("project_id = ?", projectID).Order("created_at DESC").Find(&comments)
+	config.DB.Preload("User").Where("project_id = ? AND deleted_at IS NULL", projectID).Order("created_at DESC").Find(&comments)
 
 	var response []models.CommentResponse
 	for _, comment := range comments {
@@ -167,18 +169,30 @@ func GetProjectComments(c *gin.Context) {
 	})
 }
 
-// DeleteComment - User deletes their comment
+// DeleteComment - User deletes their comment (moderators can delete any)
 func DeleteComment(c *gin.Context) {
-	userID, _ := c.Get("user_id")
 	commentID := c.Param("id")
 
-	result := config.DB.Where("id = ? AND user_id = ?", commentID, userID).Delete(&config.Comment{})
+	var comment config.Comment
+	if err := config.DB.Where("id = ?", commentID).First(&comment).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return
+	}
+	if !middleware.CanActOn(c, comment.UserID, utils.PermCommentModerate) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only delete your own comments"})
+		return
+	}
 
-	if result.RowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found or unauthorized"})
+	if err := config.DB.Delete(&comment).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment"})
 		return
 	}
 
+	utils.RecordAudit(c, "comment.delete", "comment", comment.ID, utils.AuditDiff{
+		"content":  {From: comment.Content, To: nil},
+		"owner_id": {From: comment.UserID, To: nil},
+	})
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Comment deleted",
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"jobconnect-backend/config"
+	"jobconnect-backend/models"
+	"jobconnect-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Portfolio totals per creative, joined into talent search as "p". Views and
+// likes don't touch updated_at, so last_active is the creative's own activity.
+const talentPortfolioStats = `LEFT JOIN (
+	SELECT user_id, COUNT(*) AS projects, SUM(likes_count) AS likes, SUM(views) AS views, MAX(updated_at) AS last_active
+	FROM projects WHERE deleted_at IS NULL AND status = 'published' GROUP BY user_id
+) p ON p.user_id = users.id`
+
+// Follower totals per user, joined into talent search as "f"
+const talentFollowerStats = `LEFT JOIN (
+	SELECT following_id, COUNT(*) AS followers FROM follows GROUP BY following_id
+) f ON f.following_id = users.id`
+
+// A like says more about a portfolio than a view
+const talentEngagementScore = "COALESCE(p.likes, 0) * 10 + COALESCE(p.views, 0)"
+
+var talentSortOrders = map[string]string{
+	"engagement": talentEngagementScore + " DESC, users.id",
+	"followers":  "COALESCE(f.followers, 0) DESC, users.id",
+	"recent":     "p.last_active DESC NULLS LAST, users.id",
+	"newest":     "users.created_at DESC, users.id",
+}
+
+type talentRow struct {
+	ID             uint
+	FollowersCount int64
+	ProjectsCount  int64
+	TotalLikes     int64
+	TotalViews     int64
+	LastActiveAt   *time.Time
+}
+
+// SearchTalent - Company users and admins search creatives
+//
+// Filters: q (name/bio), skills (comma-separated, all must match), location,
+// for_hire, category (slug of a category they've published in),
+// min_followers, active_within (days since last portfolio update),
+// available_from/available_to (YYYY-MM-DD; some available time in the range
+// and no bookings), rate_unit, currency and max_rate (a published rate of at
+// most max_rate in that currency).
+// sort: engagement (default), followers, recent, newest.
+func SearchTalent(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	order, ok := talentSortOrders[c.DefaultQuery("sort", "engagement")]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be one of engagement, followers, recent, newest"})
+		return
+	}
+
+	query := config.DB.Table("users").
+		Joins(talentPortfolioStats).
+		Joins(talentFollowerStats).
+		Where("users.role = ? AND users.status NOT IN ?", "creative", []string{utils.StatusBanned, utils.StatusDeleted})
+
+	if q := c.Query("q"); q != "" {
+		pattern := "%" + utils.EscapeLike(q) + "%"
+		query = query.Where("users.name ILIKE ? OR users.bio ILIKE ?", pattern, pattern)
+	}
+
+	// Whole skills only: "art" shouldn't match "cartography"
+	for _, skill := range utils.NormalizeSkills(strings.Split(c.Query("skills"), ",")) {
+		query = query.Where("',' || users.skills || ',' ILIKE ?", "%,"+utils.EscapeLike(skill)+",%")
+	}
+
+	if location := c.Query("location"); location != "" {
+		query = query.Where("users.location ILIKE ?", "%"+utils.EscapeLike(location)+"%")
+	}
+
+	if forHire := c.Query("for_hire"); forHire != "" {
+		query = query.Where("users.for_hire = ?", forHire == "true")
+	}
+
+	if categorySlug := c.Query("category"); categorySlug != "" {
+		query = query.Where(`EXISTS (SELECT 1 FROM projects cp JOIN categories ON categories.id = cp.category_id
+			WHERE cp.user_id = users.id AND cp.deleted_at IS NULL AND cp.status = 'published' AND categories.slug = ?)`, categorySlug)
+	}
+
+	if minFollowers, err := strconv.Atoi(c.Query("min_followers")); err == nil && minFollowers > 0 {
+		query = query.Where("COALESCE(f.followers, 0) >= ?", minFollowers)
+	}
+
+	if days, err := strconv.Atoi(c.Query("active_within")); err == nil && days > 0 {
+		query = query.Where("p.last_active >= ?", time.Now().AddDate(0, 0, -days))
+	}
+
+	if from, to := c.Query("available_from"), c.Query("available_to"); from != "" || to != "" {
+		if from == "" {
+			from = to
+		}
+		if to == "" {
+			to = from
+		}
+		start, errStart := time.Parse(dateLayout, from)
+		end, errEnd := time.Parse(dateLayout, to)
+		if errStart != nil || errEnd != nil || end.Before(start) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "available_from and available_to must be YYYY-MM-DD dates in order"})
+			return
+		}
+		query = query.Where(`EXISTS (SELECT 1 FROM availability_windows aw
+			WHERE aw.user_id = users.id AND aw.status = 'available' AND aw.start_date <= ? AND aw.end_date >= ?)`, end, start).
+			Where(`NOT EXISTS (SELECT 1 FROM availability_windows aw
+			WHERE aw.user_id = users.id AND aw.status = 'booked' AND aw.start_date <= ? AND aw.end_date >= ?)`, end, start)
+	}
+
+	unit, currency, maxRate := c.Query("rate_unit"), strings.ToUpper(c.Query("currency")), c.Query("max_rate")
+	if unit != "" || currency != "" || maxRate != "" {
+		rates := config.DB.Table("rate_cards rc").Select("1").Where("rc.user_id = users.id")
+		if unit != "" {
+			rates = rates.Where("rc.unit = ?", unit)
+		}
+		if currency != "" {
+			rates = rates.Where("rc.currency = ?", currency)
+		}
+		if maxRate != "" {
+			amount, err := strconv.Atoi(maxRate)
+			if err != nil || amount < 1 || currency == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "max_rate must be a positive number and needs a currency"})
+				return
+			}
+			rates = rates.Where("rc.amount <= ?", amount)
+		}
+		query = query.Where("EXISTS (?)", rates)
+	}
+
+	var totalCount int64
+	query.Session(&gorm.Session{}).Count(&totalCount)
+
+	var rows []talentRow
+	query.Select(`users.id, COALESCE(f.followers, 0) AS followers_count, COALESCE(p.projects, 0) AS projects_count,
+		COALESCE(p.likes, 0) AS total_likes, COALESCE(p.views, 0) AS total_views, p.last_active AS last_active_at`).
+		Order(order).Offset(offset).Limit(limit).Scan(&rows)
+
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	users := make(map[uint]config.User)
+	if len(ids) > 0 {
+		var found []config.User
+		config.DB.Where("id IN ?", ids).Find(&found)
+		for _, user := range found {
+			users[user.ID] = user
+		}
+	}
+
+	rates := userRateCards(ids)
+
+	response := []models.TalentResponse{}
+	for _, row := range rows {
+		user := users[row.ID]
+		response = append(response, models.TalentResponse{
+			ID:             user.ID,
+			Username:       userHandle(user),
+			Name:           user.Name,
+			Bio:            user.Bio,
+			Location:       user.Location,
+			Skills:         splitSkills(user.Skills),
+			AvatarURL:      user.AvatarURL,
+			ForHire:        user.ForHire,
+			Rates:          rates[row.ID],
+			FollowersCount: row.FollowersCount,
+			ProjectsCount:  row.ProjectsCount,
+			TotalLikes:     row.TotalLikes,
+			TotalViews:     row.TotalViews,
+			LastActiveAt:   row.LastActiveAt,
+		})
+	}
+
+	totalPages := int((totalCount + int64(limit) - 1) / int64(limit))
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"talent":     response,
+		"page":       page,
+		"totalPages": totalPages,
+		"totalCount": totalCount,
+	})
+}
@@ -0,0 +1,436 @@
+package handlers
+
+import (
+	"net/http"
+	"os"
+	"time"
+
+	"jobconnect-backend/config"
+	"jobconnect-backend/models"
+	"jobconnect-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	twoFactorChallengeTTL = 5 * time.Minute
+	recoveryCodeCount     = 10
+	totpIssuer            = "RosyArtGrid"
+)
+
+// twoFactorMandatory reports whether the user may not go without 2FA.
+// REQUIRE_ADMIN_2FA=true makes it mandatory for every admin.
+func twoFactorMandatory(user config.User) bool {
+	return user.TwoFactorRequired || (user.Role == "admin" && os.Getenv("REQUIRE_ADMIN_2FA") == "true")
+}
+
+// twoFactorEnrollmentRequired reports whether the user must set up 2FA before
+// getting a session
+func twoFactorEnrollmentRequired(user config.User) bool {
+	return !user.TOTPEnabled && twoFactorMandatory(user)
+}
+
+func sendTwoFactorChallenge(c *gin.Context, user config.User, purpose string) {
+	challenge, err := utils.GenerateActionToken(user.ID, user.Email, purpose, twoFactorChallengeTTL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":                        true,
+		"two_factor_required":            purpose == utils.PurposeLogin2FA,
+		"two_factor_enrollment_required": purpose == utils.PurposeEnroll2FA,
+		"challenge_token":                challenge,
+		"expires_in":                     int(twoFactorChallengeTTL.Seconds()),
+	})
+}
+
+// userFromChallenge resolves the user a login challenge was issued to
+func userFromChallenge(c *gin.Context, token, purpose string) (config.User, bool) {
+	var user config.User
+
+	claims, err := utils.ValidateActionToken(token, purpose)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired challenge"})
+		return user, false
+	}
+
+	if err := config.DB.Preload("Company").Where("id = ? AND email = ?", claims.UserID, claims.Email).First(&user).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired challenge"})
+		return user, false
+	}
+
+	// The account may have been suspended or banned since the password step
+	if accountBlocked(c, user) {
+		return user, false
+	}
+
+	return user, true
+}
+
+// verifyTOTP checks a code and burns its time step so it can't be replayed
+func verifyTOTP(user *config.User, code string) bool {
+	step, ok := utils.ValidateTOTP(user.TOTPSecret, code, time.Now())
+	if !ok || step <= user.TOTPLastStep {
+		return false
+	}
+
+	// Conditional update so two concurrent requests can't both use the code
+	result := config.DB.Model(&config.User{}).
+		Where("id = ? AND totp_last_step < ?", user.ID, step).
+		Update("totp_last_step", step)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false
+	}
+
+	user.TOTPLastStep = step
+	return true
+}
+
+func useRecoveryCode(userID uint, code string) bool {
+	result := config.DB.Model(&config.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, utils.HashToken(utils.NormalizeRecoveryCode(code))).
+		Update("used_at", time.Now())
+	return result.Error == nil && result.RowsAffected > 0
+}
+
+// replaceRecoveryCodes discards the user's old codes and returns fresh ones.
+// Only the hashes are stored, so this is the only time they can be shown.
+func replaceRecoveryCodes(tx *gorm.DB, userID uint) ([]string, error) {
+	codes, err := utils.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Where("user_id = ?", userID).Delete(&config.RecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	for _, code := range codes {
+		recoveryCode := config.RecoveryCode{
+			UserID:   userID,
+			CodeHash: utils.HashToken(utils.NormalizeRecoveryCode(code)),
+		}
+		if err := tx.Create(&recoveryCode).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return codes, nil
+}
+
+// startEnrollment stores a new pending secret and responds with it
+func startEnrollment(c *gin.Context, user config.User) {
+	if user.TOTPEnabled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Two-factor authentication is already enabled"})
+		return
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate secret"})
+		return
+	}
+
+	if err := config.DB.Model(&user).Update("totp_secret", secret).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start enrollment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"secret":      secret,
+		"otpauth_uri": utils.TOTPURI(secret, user.Email, totpIssuer),
+	})
+}
+
+// confirmEnrollment enables 2FA once the user proves their app has the
+// secret, returning the new recovery codes
+func confirmEnrollment(c *gin.Context, user *config.User, code string) ([]string, bool) {
+	if user.TOTPEnabled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Two-factor authentication is already enabled"})
+		return nil, false
+	}
+	if user.TOTPSecret == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Start two-factor setup first"})
+		return nil, false
+	}
+	if !verifyTOTP(user, code) {
+		recordLoginFailure(c, user.Email)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authentication code"})
+		return nil, false
+	}
+
+	var codes []string
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).Update("totp_enabled", true).Error; err != nil {
+			return err
+		}
+		var err error
+		codes, err = replaceRecoveryCodes(tx, user.ID)
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enable two-factor authentication"})
+		return nil, false
+	}
+
+	utils.RecordAuditAs(c, user.ID, "auth.2fa_enabled", "user", user.ID, nil)
+
+	return codes, true
+}
+
+// SetupTwoFactor - Start TOTP enrollment for the current user
+func SetupTwoFactor(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var user config.User
+	if err := config.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	startEnrollment(c, user)
+}
+
+// ConfirmTwoFactor - Finish TOTP enrollment with a code from the app
+func ConfirmTwoFactor(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user config.User
+	if err := config.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	codes, ok := confirmEnrollment(c, &user, req.Code)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":        true,
+		"message":        "Two-factor authentication enabled",
+		"recovery_codes": codes,
+	})
+}
+
+// DisableTwoFactor - Turn off 2FA (requires password and a current code)
+func DisableTwoFactor(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.DisableTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user config.User
+	if err := config.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if !user.TOTPEnabled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Two-factor authentication is not enabled"})
+		return
+	}
+
+	if twoFactorMandatory(user) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Two-factor authentication is required for this account"})
+		return
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Password is incorrect"})
+		return
+	}
+	if !verifyTOTP(&user, req.Code) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authentication code"})
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"totp_enabled": false,
+			"totp_secret":  "",
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", user.ID).Delete(&config.RecoveryCode{}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to disable two-factor authentication"})
+		return
+	}
+
+	utils.RecordAudit(c, "auth.2fa_disabled", "user", user.ID, nil)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Two-factor authentication disabled",
+	})
+}
+
+// RegenerateRecoveryCodes - Replace all recovery codes (requires a current code)
+func RegenerateRecoveryCodes(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user config.User
+	if err := config.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if !user.TOTPEnabled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Two-factor authentication is not enabled"})
+		return
+	}
+	if !verifyTOTP(&user, req.Code) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authentication code"})
+		return
+	}
+
+	codes, err := replaceRecoveryCodes(config.DB, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate recovery codes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":        true,
+		"recovery_codes": codes,
+	})
+}
+
+// VerifyTwoFactorLogin - Second login step: exchange challenge + code for a session
+func VerifyTwoFactorLogin(c *gin.Context) {
+	var req models.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Code == "" && req.RecoveryCode == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code or recovery_code is required"})
+		return
+	}
+
+	user, ok := userFromChallenge(c, req.ChallengeToken, utils.PurposeLogin2FA)
+	if !ok {
+		return
+	}
+
+	// Codes are only 6 digits, so they share the password guessing limits
+	if retryAfter, _ := utils.GetLoginLimiter().RetryAfter(user.Email, c.ClientIP()); retryAfter > 0 {
+		tooManyLoginAttempts(c, retryAfter)
+		return
+	}
+
+	verified := false
+	if req.Code != "" {
+		verified = verifyTOTP(&user, req.Code)
+	} else {
+		verified = useRecoveryCode(user.ID, req.RecoveryCode)
+	}
+	if !verified {
+		recordLoginFailure(c, user.Email)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authentication code"})
+		return
+	}
+
+	completeLogin(c, user, nil)
+}
+
+// EnrollTwoFactorChallenge - Forced enrollment during login, step one
+func EnrollTwoFactorChallenge(c *gin.Context) {
+	var req models.TwoFactorEnrollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, ok := userFromChallenge(c, req.ChallengeToken, utils.PurposeEnroll2FA)
+	if !ok {
+		return
+	}
+
+	startEnrollment(c, user)
+}
+
+// ConfirmTwoFactorChallenge - Forced enrollment during login, step two.
+// Returns recovery codes together with the session tokens.
+func ConfirmTwoFactorChallenge(c *gin.Context) {
+	var req models.TwoFactorEnrollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code is required"})
+		return
+	}
+
+	user, ok := userFromChallenge(c, req.ChallengeToken, utils.PurposeEnroll2FA)
+	if !ok {
+		return
+	}
+
+	if retryAfter, _ := utils.GetLoginLimiter().RetryAfter(user.Email, c.ClientIP()); retryAfter > 0 {
+		tooManyLoginAttempts(c, retryAfter)
+		return
+	}
+
+	codes, ok := confirmEnrollment(c, &user, req.Code)
+	if !ok {
+		return
+	}
+
+	completeLogin(c, user, codes)
+}
+
+// RequireTwoFactor - Admin forces (or stops forcing) 2FA enrollment for a user
+func RequireTwoFactor(c *gin.Context) {
+	userID := c.Param("id")
+
+	var req struct {
+		Required *bool `json:"required" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result := config.DB.Model(&config.User{}).Where("id = ?", userID).Update("two_factor_required", *req.Required)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	utils.RecordAudit(c, "user.require_2fa", "user", userID, utils.AuditDiff{
+		"two_factor_required": {From: nil, To: *req.Required},
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Two-factor requirement updated",
+	})
+}
@@ -0,0 +1,34 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"jobconnect-backend/config"
+	"jobconnect-backend/utils"
+)
+
+func TestTwoFactorLoginRechecksAccountStatus(t *testing.T) {
+	r, _ := setupServer(t)
+
+	userID := register(t, r, "ada@example.com")
+	config.DB.Model(&config.User{}).Where("id = ?", userID).Update("totp_enabled", true)
+	config.DB.Create(&config.RecoveryCode{UserID: userID, CodeHash: utils.HashToken(utils.NormalizeRecoveryCode("abcd-efgh"))})
+
+	status, body := doJSON(t, r, http.MethodPost, "/api/auth/login", "", map[string]string{"email": "ada@example.com", "password": "secret123"})
+	if status != http.StatusOK || body["two_factor_required"] != true {
+		t.Fatalf("login: got %d %v", status, body)
+	}
+	challenge := body["challenge_token"].(string)
+
+	// Banned between the password and the code
+	config.DB.Model(&config.User{}).Where("id = ?", userID).Update("status", utils.StatusBanned)
+
+	status, body = doJSON(t, r, http.MethodPost, "/api/auth/2fa/verify", "", map[string]string{"challenge_token": challenge, "recovery_code": "abcd-efgh"})
+	if status != http.StatusForbidden {
+		t.Fatalf("banned user: got %d %v", status, body)
+	}
+	if body["token"] != nil {
+		t.Fatal("banned user got a token")
+	}
+}
@@ -3,8 +3,12 @@ package handlers
 import (
 	"context"
 	"jobconnect-backend/config"
+	"jobconnect-backend/utils"
+	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/cloudinary/cloudinary-go/v2"
 	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
@@ -49,6 +53,13 @@ func UploadImage(c *gin.Context) {
 		return
 	}
 
+	userID, _ := c.Get("user_id")
+	recordUpload(userID.(uint), uploadResult.PublicID, uploadResult.SecureURL)
+
+	utils.RecordAudit(c, "upload.image", "image", uploadResult.PublicID, utils.AuditDiff{
+		"url": {From: nil, To: uploadResult.SecureURL},
+	})
+
 	c.JSON(http.StatusOK, gin.H{
 		"success":   true,
 		"image_url": uploadResult.SecureURL,
@@ -80,6 +91,7 @@ func UploadMultipleImages(c *gin.Context) {
 		return
 	}
 
+	userID, _ := c.Get("user_id")
 	var imageURLs []string
 	ctx := context.Background()
 
@@ -96,6 +108,7 @@ func UploadMultipleImages(c *gin.Context) {
 		fileContent.Close()
 
 		if err == nil {
+			recordUpload(userID.(uint), uploadResult.PublicID, uploadResult.SecureURL)
 			imageURLs = append(imageURLs, uploadResult.SecureURL)
 		}
 	}
@@ -105,6 +118,10 @@ func UploadMultipleImages(c *gin.Context) {
 		return
 	}
 
+	utils.RecordAudit(c, "upload.images", "image", "", utils.AuditDiff{
+		"urls": {From: nil, To: imageURLs},
+	})
+
 	c.JSON(http.StatusOK, gin.H{
 		"success":    true,
 		"image_urls": imageURLs,
@@ -152,10 +169,130 @@ func UploadAvatar(c *gin.Context) {
 	}
 
 	// Update user avatar
+	recordUpload(userID.(uint), uploadResult.PublicID, uploadResult.SecureURL)
 	config.DB.Model(&config.User{}).Where("id = ?", userID).Update("avatar_url", uploadResult.SecureURL)
 
+	utils.RecordAudit(c, "upload.avatar", "user", userID, utils.AuditDiff{
+		"avatar_url": {From: nil, To: uploadResult.SecureURL},
+	})
+
 	c.JSON(http.StatusOK, gin.H{
 		"success":    true,
 		"avatar_url": uploadResult.SecureURL,
 	})
 }
+
+// recordUpload remembers that the user uploaded an image
+func recordUpload(userID uint, publicID, imageURL string) {
+	upload := config.UploadedImage{UserID: userID, PublicID: publicID, ImageURL: imageURL}
+	if err := config.DB.Create(&upload).Error; err != nil {
+		log.Println("Warning: Failed to record upload:", err)
+	}
+}
+
+// foreignImage returns the first of imageURLs the user didn't upload through
+// us, or "" when they uploaded all of them
+func foreignImage(userID uint, imageURLs []string) string {
+	if len(imageURLs) == 0 {
+		return ""
+	}
+
+	var own []string
+	config.DB.Model(&config.UploadedImage{}).Where("user_id = ? AND image_url IN ?", userID, imageURLs).Pluck("image_url", &own)
+	uploaded := make(map[string]bool)
+	for _, imageURL := range own {
+		uploaded[imageURL] = true
+	}
+	for _, imageURL := range imageURLs {
+		if !uploaded[imageURL] {
+			return imageURL
+		}
+	}
+	return ""
+}
+
+// imageInUse reports whether any project, revision, avatar or company logo
+// still shows the image with the given public ID, under any delivery URL.
+// Errors count as in use, so a failed lookup never destroys an image.
+func imageInUse(publicID string) bool {
+	pattern := "%" + publicID + "%"
+	var imageURLs []string
+	err := config.DB.Raw(`SELECT image_url FROM project_images WHERE image_url LIKE ?
+		UNION SELECT url FROM project_revisions, jsonb_array_elements_text(image_urls) AS url WHERE url LIKE ?
+		UNION SELECT avatar_url FROM users WHERE avatar_url LIKE ?
+		UNION SELECT logo_url FROM companies WHERE logo_url LIKE ?`, pattern, pattern, pattern, pattern).
+		Scan(&imageURLs).Error
+	if err != nil {
+		log.Println("Warning: Failed to check image references:", err)
+		return true
+	}
+	for _, imageURL := range imageURLs {
+		if cloudinaryPublicID(imageURL) == publicID {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteUserImages removes a deleted user's images from Cloudinary. Run it
+// once their rows are gone. An image is only destroyed when nobody else
+// uploaded it and nothing left in the database shows it; URLs that weren't
+// uploaded to our cloud are skipped. Failures are only logged.
+func deleteUserImages(userID uint, imageURLs []string) {
+	if len(imageURLs) == 0 {
+		return
+	}
+
+	cld, err := cloudinary.NewFromParams(
+		os.Getenv("CLOUDINARY_CLOUD_NAME"),
+		os.Getenv("CLOUDINARY_API_KEY"),
+		os.Getenv("CLOUDINARY_API_SECRET"),
+	)
+	if err != nil {
+		log.Println("Warning: Failed to setup Cloudinary:", err)
+		return
+	}
+
+	ctx := context.Background()
+	seen := make(map[string]bool)
+	for _, imageURL := range imageURLs {
+		publicID := cloudinaryPublicID(imageURL)
+		if publicID == "" || seen[publicID] {
+			continue
+		}
+		seen[publicID] = true
+
+		// Images from before uploads were recorded have no uploader at all
+		var others int64
+		config.DB.Model(&config.UploadedImage{}).Where("public_id = ? AND user_id <> ?", publicID, userID).Count(&others)
+		if others > 0 || imageInUse(publicID) {
+			continue
+		}
+
+		if _, err := cld.Upload.Destroy(ctx, uploader.DestroyParams{PublicID: publicID}); err != nil {
+			log.Println("Warning: Failed to delete Cloudinary image", publicID+":", err)
+			continue
+		}
+		config.DB.Where("public_id = ?", publicID).Delete(&config.UploadedImage{})
+	}
+}
+
+// cloudinaryPublicID extracts the public ID from a delivery URL such as
+// https://res.cloudinary.com/<cloud>/image/upload/v123/rosyartgrid/projects/abc.jpg
+func cloudinaryPublicID(imageURL string) string {
+	prefix := "https://res.cloudinary.com/" + os.Getenv("CLOUDINARY_CLOUD_NAME") + "/image/upload/"
+	if !strings.HasPrefix(imageURL, prefix) {
+		return ""
+	}
+
+	path := strings.TrimPrefix(imageURL, prefix)
+	if parts := strings.SplitN(path, "/", 2); len(parts) == 2 && len(parts[0]) > 1 && parts[0][0] == 'v' {
+		if _, err := strconv.Atoi(parts[0][1:]); err == nil {
+			path = parts[1]
+		}
+	}
+	if dot := strings.LastIndex(path, "."); dot > 0 {
+		path = path[:dot]
+	}
+	return path
+}
@@ -0,0 +1,319 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"jobconnect-backend/config"
+	"jobconnect-backend/models"
+	"jobconnect-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetUserProfile - Public profile of a user
+func GetUserProfile(c *gin.Context) {
+	var user config.User
+	if err := config.DB.Where("id = ? AND status NOT IN ?", c.Param("id"), []string{utils.StatusBanned, utils.StatusDeleted}).
+		First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"user":    buildPublicProfile(c, user),
+	})
+}
+
+// buildPublicProfile fills in the public view of a user, with counts and the
+// viewer's follow state. Email is only shown to the owner.
+func buildPublicProfile(c *gin.Context, user config.User) models.PublicProfileResponse {
+	profile := models.PublicProfileResponse{
+		ID:           user.ID,
+		Username:     userHandle(user),
+		Name:         user.Name,
+		Role:         user.Role,
+		Bio:          user.Bio,
+		Location:     user.Location,
+		Skills:       splitSkills(user.Skills),
+		Website:      user.Website,
+		BehanceURL:   user.BehanceURL,
+		DribbbleURL:  user.DribbbleURL,
+		LinkedInURL:  user.LinkedInURL,
+		TwitterURL:   user.TwitterURL,
+		AvatarURL:    user.AvatarURL,
+		ForHire:      user.ForHire,
+		Availability: upcomingAvailability(user.ID),
+		Rates:        rateCardsOf(user.ID),
+		CreatedAt:    user.CreatedAt,
+	}
+
+	config.DB.Model(&config.Follow{}).Where("following_id = ?", user.ID).Count(&profile.FollowersCount)
+	config.DB.Model(&config.Follow{}).Where("follower_id = ?", user.ID).Count(&profile.FollowingCount)
+	config.DB.Model(&config.Project{}).Where("user_id = ? AND deleted_at IS NULL AND status = ?", user.ID, projectPublished).Count(&profile.ProjectsCount)
+
+	if viewerID, ok := c.Get("user_id"); ok {
+		if viewerID.(uint) == user.ID {
+			profile.Email = user.Email
+		} else {
+			var count int64
+			config.DB.Model(&config.Follow{}).Where("follower_id = ? AND following_id = ?", viewerID, user.ID).Count(&count)
+			profile.IsFollowing = count > 0
+		}
+	}
+
+	return profile
+}
+
+// GetUserProjects - Public portfolio of a user
+func GetUserProjects(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	var user config.User
+	if err := config.DB.Where("id = ? AND status NOT IN ?", c.Param("id"), []string{utils.StatusBanned, utils.StatusDeleted}).
+		First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	var projects []config.Project
+	var totalCount int64
+
+	query := config.DB.Preload("Category").Preload("Images").
+		Where("user_id = ? AND deleted_at IS NULL AND status = ?", user.ID, projectPublished)
+
+	query.Model(&config.Project{}).Count(&totalCount)
+	query.Offset(offset).Limit(limit).Order("publish_at DESC").Find(&projects)
+
+	var response []models.ProjectResponse
+	for _, project := range projects {
+		var images []models.ProjectImageResponse
+		for _, img := range project.Images {
+			images = append(images, models.ProjectImageResponse{
+				ID:       img.ID,
+				ImageURL: img.ImageURL,
+				Order:    img.Order,
+			})
+		}
+
+		response = append(response, models.ProjectResponse{
+			ID:          project.ID,
+			Title:       project.Title,
+			Description: project.Description,
+			CoverImage:  project.CoverImage,
+			Images:      images,
+			User: models.UserResponse{
+				ID:        user.ID,
+				Name:      user.Name,
+				AvatarURL: user.AvatarURL,
+			},
+			Category: models.CategoryResponse{
+				ID:   project.Category.ID,
+				Name: project.Category.Name,
+				Slug: project.Category.Slug,
+				Icon: project.Category.Icon,
+			},
+			Tags:       project.Tags,
+			Views:      project.Views,
+			LikesCount: project.LikesCount,
+			Featured:   project.Featured,
+			CreatedAt:  project.CreatedAt,
+		})
+	}
+
+	totalPages := int((totalCount + int64(limit) - 1) / int64(limit))
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"projects":   response,
+		"page":       page,
+		"totalPages": totalPages,
+		"totalCount": totalCount,
+	})
+}
+
+// splitSkills turns the comma-separated Skills column into a list
+func splitSkills(skills string) []string {
+	list := []string{}
+	for _, skill := range strings.Split(skills, ",") {
+		if skill = strings.TrimSpace(skill); skill != "" {
+			list = append(list, skill)
+		}
+	}
+	return list
+}
+
+// userSummary is the short form of a user embedded in other responses
+func userSummary(user config.User) models.UserResponse {
+	return models.UserResponse{
+		ID:        user.ID,
+		Username:  userHandle(user),
+		Name:      user.Name,
+		AvatarURL: user.AvatarURL,
+	}
+}
+
+// userHandle is the user's handle, or "" if they haven't picked one
+func userHandle(user config.User) string {
+	if user.Username == nil {
+		return ""
+	}
+	return *user.Username
+}
+
+// handleTaken reports whether a handle belongs to, or redirects to, a user
+// other than userID
+func handleTaken(handle string, userID uint) bool {
+	normalized := utils.NormalizeHandle(handle)
+
+	var count int64
+	config.DB.Model(&config.User{}).Where("LOWER(username) = ? AND id <> ?", normalized, userID).Count(&count)
+	if count > 0 {
+		return true
+	}
+	config.DB.Model(&config.HandleRedirect{}).Where("handle = ? AND user_id <> ?", normalized, userID).Count(&count)
+	return count > 0
+}
+
+// CheckHandleAvailability - Check whether a handle can be claimed
+func CheckHandleAvailability(c *gin.Context) {
+	handle := strings.TrimPrefix(c.Param("handle"), "@")
+
+	// Signed-in users may "re-claim" their own current or old handles
+	var userID uint
+	if id, ok := c.Get("user_id"); ok {
+		userID = id.(uint)
+	}
+
+	if err := utils.ValidateHandle(handle); err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"success":   true,
+			"handle":    handle,
+			"available": false,
+			"reason":    err.Error(),
+		})
+		return
+	}
+
+	if handleTaken(handle, userID) {
+		c.JSON(http.StatusOK, gin.H{
+			"success":   true,
+			"handle":    handle,
+			"available": false,
+			"reason":    "handle is already taken",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"handle":    handle,
+		"available": true,
+	})
+}
+
+// SetHandle - Claim or change the current user's handle. The old handle keeps
+// redirecting to the user.
+func SetHandle(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.SetHandleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	handle := strings.TrimPrefix(strings.TrimSpace(req.Handle), "@")
+	if err := utils.ValidateHandle(handle); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user config.User
+	if err := config.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if handleTaken(handle, user.ID) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Handle is already taken"})
+		return
+	}
+
+	previous := userHandle(user)
+	normalized := utils.NormalizeHandle(handle)
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		// Getting an old handle back removes its redirect
+		if err := tx.Where("handle = ? AND user_id = ?", normalized, user.ID).Delete(&config.HandleRedirect{}).Error; err != nil {
+			return err
+		}
+		if previous != "" && utils.NormalizeHandle(previous) != normalized {
+			if err := tx.Create(&config.HandleRedirect{
+				Handle: utils.NormalizeHandle(previous),
+				UserID: user.ID,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&user).Update("username", handle).Error
+	})
+	// Someone else can claim the handle between handleTaken and the update;
+	// the unique index on LOWER(username) catches them
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Handle is already taken"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update handle"})
+		return
+	}
+
+	utils.RecordAudit(c, "profile.handle_change", "user", user.ID, utils.AuditDiff{
+		"username": {From: previous, To: handle},
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"message":  "Handle updated",
+		"username": handle,
+	})
+}
+
+// GetUserByHandle - Public profile looked up by handle. Old handles answer
+// with a permanent redirect to the current one.
+func GetUserByHandle(c *gin.Context) {
+	normalized := utils.NormalizeHandle(c.Param("handle"))
+
+	var user config.User
+	if err := config.DB.Where("LOWER(username) = ? AND status NOT IN ?", normalized, []string{utils.StatusBanned, utils.StatusDeleted}).
+		First(&user).Error; err == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"user":    buildPublicProfile(c, user),
+		})
+		return
+	}
+
+	var redirect config.HandleRedirect
+	if err := config.DB.Where("handle = ?", normalized).First(&redirect).Error; err == nil {
+		if err := config.DB.Where("id = ? AND username IS NOT NULL AND status NOT IN ?", redirect.UserID, []string{utils.StatusBanned, utils.StatusDeleted}).
+			First(&user).Error; err == nil {
+			c.Redirect(http.StatusMovedPermanently, "/api/u/"+*user.Username)
+			return
+		}
+	}
+
+	c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+}
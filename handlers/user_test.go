@@ -0,0 +1,35 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"jobconnect-backend/config"
+
+	"gorm.io/gorm"
+)
+
+func TestSetHandleLosingARaceIsAConflict(t *testing.T) {
+	r, _ := setupServer(t)
+
+	if err := config.DB.Exec(`CREATE UNIQUE INDEX idx_users_username_lower ON users (LOWER(username))`).Error; err != nil {
+		t.Fatal(err)
+	}
+	bobID, _ := creative(t, r, "bob@example.com")
+	_, adaToken := creative(t, r, "ada@example.com")
+
+	// Bob claims the handle after Ada's availability check but before her update
+	raced := false
+	config.DB.Callback().Update().Before("gorm:update").Register("test:race", func(tx *gorm.DB) {
+		if raced || tx.Statement.Table != "users" {
+			return
+		}
+		raced = true
+		tx.Session(&gorm.Session{NewDB: true}).Exec("UPDATE users SET username = ? WHERE id = ?", "Painter", bobID)
+	})
+
+	status, body := doJSON(t, r, http.MethodPut, "/api/profile/handle", adaToken, map[string]string{"handle": "painter"})
+	if status != http.StatusConflict {
+		t.Fatalf("claiming a handle taken mid-request: got %d %v, want 409", status, body)
+	}
+}
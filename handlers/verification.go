@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"jobconnect-backend/config"
+	"jobconnect-backend/models"
+	"jobconnect-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const verificationTokenTTL = 48 * time.Hour
+
+// sendVerificationEmail mails a signed verification link to the user
+func sendVerificationEmail(user config.User) error {
+	token, err := utils.GenerateActionToken(user.ID, user.Email, utils.PurposeVerifyEmail, verificationTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(
+		"Hi %s,\n\nPlease confirm your email address for RosyArtGrid:\n\n%s\n\nThis link expires in %d hours.",
+		user.Name, utils.FrontendLink("/verify-email", token), int(verificationTokenTTL.Hours()),
+	)
+	return utils.GetMailer().Send(user.Email, "Confirm your RosyArtGrid email", body)
+}
+
+// VerifyEmail - Confirm an email address with the token from the verification mail
+func VerifyEmail(c *gin.Context) {
+	var req models.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims, err := utils.ValidateActionToken(req.Token, utils.PurposeVerifyEmail)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired verification token"})
+		return
+	}
+
+	// The email is part of the token so a link stops working if the address
+	// changes, and a link only works once
+	result := config.DB.Model(&config.User{}).
+		Where("id = ? AND email = ? AND verified = ?", claims.UserID, claims.Email, false).
+		Update("verified", true)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify email"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired verification token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Email verified",
+	})
+}
+
+// ResendVerification - Send a fresh verification email
+func ResendVerification(c *gin.Context) {
+	var req models.EmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if mailThrottled(c, "verify", req.Email) {
+		return
+	}
+
+	var user config.User
+	if err := config.DB.Where("email = ?", req.Email).First(&user).Error; err == nil && !user.Verified {
+		if err := sendVerificationEmail(user); err != nil {
+			log.Println("Warning: Failed to send verification email:", err)
+		}
+	}
+
+	// Same response whether or not the account exists
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "If the account exists and is unverified, a verification email has been sent",
+	})
+}
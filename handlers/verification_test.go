@@ -0,0 +1,176 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"jobconnect-backend/config"
+	"jobconnect-backend/utils"
+)
+
+func register(t *testing.T, r http.Handler, email string) uint {
+	t.Helper()
+
+	status, body := doJSON(t, r, http.MethodPost, "/api/auth/register", "", map[string]string{
+		"name":     "Ada",
+		"email":    email,
+		"password": "secret123",
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("register: got %d %v", status, body)
+	}
+	user := body["user"].(map[string]interface{})
+	return uint(user["id"].(float64))
+}
+
+// login signs in a user registered with register and returns the access token
+func login(t *testing.T, r http.Handler, email string) string {
+	t.Helper()
+
+	status, body := doJSON(t, r, http.MethodPost, "/api/auth/login", "", map[string]string{"email": email, "password": "secret123"})
+	if status != http.StatusOK {
+		t.Fatalf("login: got %d %v", status, body)
+	}
+	return body["token"].(string)
+}
+
+func isVerified(t *testing.T, userID uint) bool {
+	t.Helper()
+
+	var user config.User
+	if err := config.DB.First(&user, userID).Error; err != nil {
+		t.Fatal(err)
+	}
+	return user.Verified
+}
+
+func TestRegisterVerifyResend(t *testing.T) {
+	r, mailer := setupServer(t)
+
+	userID := register(t, r, "ada@example.com")
+	if isVerified(t, userID) {
+		t.Fatal("new account is already verified")
+	}
+
+	// Asking again mails a fresh link
+	status, body := doJSON(t, r, http.MethodPost, "/api/auth/verify/resend", "", map[string]string{"email": "ada@example.com"})
+	if status != http.StatusOK {
+		t.Fatalf("resend: got %d %v", status, body)
+	}
+	if len(mailer.Sent) != 2 {
+		t.Fatalf("expected 2 mails, got %d", len(mailer.Sent))
+	}
+	token := mailedToken(t, mailer, "ada@example.com")
+
+	status, body = doJSON(t, r, http.MethodPost, "/api/auth/verify", "", map[string]string{"token": token})
+	if status != http.StatusOK {
+		t.Fatalf("verify: got %d %v", status, body)
+	}
+	if !isVerified(t, userID) {
+		t.Fatal("account not verified")
+	}
+
+	// Verified accounts get nothing, with the same answer as anyone else
+	status, _ = doJSON(t, r, http.MethodPost, "/api/auth/verify/resend", "", map[string]string{"email": "ada@example.com"})
+	if status != http.StatusOK {
+		t.Fatalf("resend after verify: got %d", status)
+	}
+	status, _ = doJSON(t, r, http.MethodPost, "/api/auth/verify/resend", "", map[string]string{"email": "nobody@example.com"})
+	if status != http.StatusOK {
+		t.Fatalf("resend for unknown email: got %d", status)
+	}
+	if len(mailer.Sent) != 2 {
+		t.Fatalf("expected no more mail, got %d", len(mailer.Sent))
+	}
+}
+
+func TestVerifyRejectsExpiredToken(t *testing.T) {
+	r, _ := setupServer(t)
+
+	userID := register(t, r, "ada@example.com")
+	expired, err := utils.GenerateActionToken(userID, "ada@example.com", utils.PurposeVerifyEmail, -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status, _ := doJSON(t, r, http.MethodPost, "/api/auth/verify", "", map[string]string{"token": expired})
+	if status != http.StatusBadRequest {
+		t.Fatalf("expired token: got %d, want 400", status)
+	}
+	if isVerified(t, userID) {
+		t.Fatal("expired token verified the account")
+	}
+}
+
+func TestVerifyRejectsReusedToken(t *testing.T) {
+	r, mailer := setupServer(t)
+
+	register(t, r, "ada@example.com")
+	token := mailedToken(t, mailer, "ada@example.com")
+
+	status, _ := doJSON(t, r, http.MethodPost, "/api/auth/verify", "", map[string]string{"token": token})
+	if status != http.StatusOK {
+		t.Fatalf("first use: got %d", status)
+	}
+	status, _ = doJSON(t, r, http.MethodPost, "/api/auth/verify", "", map[string]string{"token": token})
+	if status != http.StatusBadRequest {
+		t.Fatalf("second use: got %d, want 400", status)
+	}
+}
+
+func TestVerifyRejectsOtherTokens(t *testing.T) {
+	r, _ := setupServer(t)
+
+	userID := register(t, r, "ada@example.com")
+
+	// A 2FA challenge for the same user is not a verification link
+	challenge, err := utils.GenerateActionToken(userID, "ada@example.com", utils.PurposeLogin2FA, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, _ := doJSON(t, r, http.MethodPost, "/api/auth/verify", "", map[string]string{"token": challenge})
+	if status != http.StatusBadRequest {
+		t.Fatalf("2FA challenge: got %d, want 400", status)
+	}
+
+	// Nor is a link for an address the account no longer has
+	stale, err := utils.GenerateActionToken(userID, "old@example.com", utils.PurposeVerifyEmail, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, _ = doJSON(t, r, http.MethodPost, "/api/auth/verify", "", map[string]string{"token": stale})
+	if status != http.StatusBadRequest {
+		t.Fatalf("token for old address: got %d, want 400", status)
+	}
+	if isVerified(t, userID) {
+		t.Fatal("account verified by a foreign token")
+	}
+}
+
+func TestResendVerificationIsThrottled(t *testing.T) {
+	r, mailer := setupServer(t)
+
+	register(t, r, "ada@example.com")
+	resend := func() int {
+		status, _ := doJSON(t, r, http.MethodPost, "/api/auth/verify/resend", "", map[string]string{"email": "ada@example.com"})
+		return status
+	}
+	for i := 0; i < 3; i++ {
+		if status := resend(); status != http.StatusOK {
+			t.Fatalf("request %d: got %d", i+1, status)
+		}
+	}
+	if status := resend(); status != http.StatusTooManyRequests {
+		t.Fatalf("fourth request: got %d, want 429", status)
+	}
+	if len(mailer.Sent) != 4 { // sign-up mail plus three resends
+		t.Fatalf("expected 4 mails, got %d", len(mailer.Sent))
+	}
+
+	// Reset links are counted separately
+	status, _ := doJSON(t, r, http.MethodPost, "/api/auth/forgot-password", "", map[string]string{"email": "ada@example.com"})
+	if status != http.StatusOK {
+		t.Fatalf("forgot password: got %d", status)
+	}
+}
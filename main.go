@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"jobconnect-backend/config"
+	"jobconnect-backend/handlers"
 	"jobconnect-backend/routes"
+	"jobconnect-backend/utils"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -56,6 +59,11 @@ func main() {
 		log.Println("No .env file found")
 	}
 
+	// Load JWT signing keys
+	if err := utils.InitKeys(); err != nil {
+		log.Fatal("Failed to load JWT keys:", err)
+	}
+
 	// Initialize database
 	config.InitDB()
 	defer config.CloseDB()
@@ -63,6 +71,15 @@ func main() {
 	// Auto-create admin user if it doesn't exist
 	createDefaultAdmin()
 
+	// Install default role permissions on first start
+	utils.SeedPermissions()
+
+	// Purge accounts whose deletion grace period has ended
+	go handlers.RunAccountPurger(time.Hour)
+
+	// Publish scheduled projects when they're due
+	go handlers.RunProjectPublisher(time.Minute)
+
 	// Setup Gin router
 	r := gin.Default()
 
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+
+	"jobconnect-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HasPermission reports whether the authenticated user's role grants perm
+func HasPermission(c *gin.Context, perm string) bool {
+	role, _ := c.Get("user_role")
+	roleName, _ := role.(string)
+	return roleName != "" && utils.RoleHasPermission(roleName, perm)
+}
+
+// CanActOn reports whether the authenticated user owns a resource or holds the
+// permission to act on resources of any owner
+func CanActOn(c *gin.Context, ownerID uint, anyPerm string) bool {
+	userID, _ := c.Get("user_id")
+	if id, ok := userID.(uint); ok && id == ownerID {
+		return true
+	}
+	return HasPermission(c, anyPerm)
+}
+
+// RequirePermission allows the request only if the user's role grants every
+// listed permission. Must run after AuthMiddleware.
+func RequirePermission(perms ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, perm := range perms {
+			if !HasPermission(c, perm) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to do this"})
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // Auth requests
 type RegisterRequest struct {
@@ -15,19 +18,134 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
-// Project requests
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type EmailRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+// Two-factor requests
+type TOTPCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code"`
+	RecoveryCode   string `json:"recovery_code"`
+}
+
+type TwoFactorEnrollRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code"`
+}
+
+type OIDCCallbackRequest struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
+
+// API key request
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1"`
+	ExpiresInDays int      `json:"expires_in_days"` // 0 = never expires
+}
+
+// Project requests. Drafts may be saved without a description or images;
+// both are needed to publish.
 type CreateProjectRequest struct {
-	Title       string   `json:"title" binding:"required"`
-	Description string   `json:"description" binding:"required"`
-	CategoryID  uint     `json:"category_id" binding:"required"`
-	Tags        string   `json:"tags"`                          // Comma-separated
-	ImageURLs   []string `json:"image_urls" binding:"required"` // Already uploaded to Cloudinary
+	Title       string     `json:"title" binding:"required"`
+	Description string     `json:"description"`
+	CategoryID  uint       `json:"category_id" binding:"required"`
+	Tags        string     `json:"tags"`       // Comma-separated
+	ImageURLs   []string   `json:"image_urls"` // Already uploaded to Cloudinary
+	Status      string     `json:"status" binding:"omitempty,oneof=draft scheduled published"`
+	PublishAt   *time.Time `json:"publish_at"` // Required when scheduling
 }
 
 type UpdateProjectRequest struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Tags        string `json:"tags"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Tags        string    `json:"tags"`
+	ImageURLs   *[]string `json:"image_urls"` // Replaces the image list, cover first; left alone when missing
+}
+
+type ProjectStatusRequest struct {
+	Status    string     `json:"status" binding:"required,oneof=draft scheduled published archived"`
+	PublishAt *time.Time `json:"publish_at"` // Required when scheduling
+}
+
+// Admin requests
+type ChangeRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=creative company admin"`
+}
+
+type SuspendUserRequest struct {
+	Days   int    `json:"days" binding:"required,min=1,max=365"`
+	Reason string `json:"reason" binding:"required"`
+}
+
+type AccountStatusRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// UpdateProfileRequest has PATCH semantics: only fields present in the body
+// are changed. Send "" to clear a link.
+type UpdateProfileRequest struct {
+	Name        *string   `json:"name" binding:"omitempty,min=1,max=255"`
+	Location    *string   `json:"location" binding:"omitempty,max=255"`
+	Bio         *string   `json:"bio" binding:"omitempty,max=5000"`
+	Skills      *[]string `json:"skills" binding:"omitempty,max=30,dive,max=50"`
+	Website     *string   `json:"website"`
+	BehanceURL  *string   `json:"behance_url"`
+	DribbbleURL *string   `json:"dribbble_url"`
+	LinkedInURL *string   `json:"linkedin_url"`
+	TwitterURL  *string   `json:"twitter_url"`
+	ForHire     *bool     `json:"for_hire"`
+}
+
+type SetHandleRequest struct {
+	Handle string `json:"handle" binding:"required"`
+}
+
+// DeleteAccountRequest confirms a deletion with the password. Without one, a
+// confirmation link is emailed instead (e.g. for social-login accounts).
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+type ConfirmAccountDeletionRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ImpersonateRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type FeatureProjectRequest struct {
+	Featured *bool `json:"featured" binding:"required"`
 }
 
 // Comment request
@@ -45,31 +163,210 @@ type CreateCompanyRequest struct {
 	Size        string `json:"size"`
 }
 
+// UpdateCompanyRequest is a partial update; fields left out are not touched
+type UpdateCompanyRequest struct {
+	Name        *string `json:"name" binding:"omitempty,min=1,max=255"`
+	Description *string `json:"description"`
+	Website     *string `json:"website"`
+	Location    *string `json:"location" binding:"omitempty,max=255"`
+	Industry    *string `json:"industry" binding:"omitempty,max=100"`
+	Size        *string `json:"size" binding:"omitempty,max=50"`
+}
+
+type InviteRecruiterRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type AcceptInvitationRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ShortlistRequest struct {
+	Name        string `json:"name" binding:"required,max=255"`
+	Description string `json:"description"`
+}
+
+type AddShortlistCandidateRequest struct {
+	UserID uint   `json:"user_id" binding:"required"`
+	Status string `json:"status" binding:"omitempty,oneof=new contacted interviewing hired"`
+	Note   string `json:"note"`
+}
+
+type ShortlistStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=new contacted interviewing hired"`
+}
+
+type ShortlistNoteRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+type CreateInquiryRequest struct {
+	CreativeID uint   `json:"creative_id" binding:"required"`
+	Title      string `json:"title" binding:"required,max=255"`
+	Brief      string `json:"brief" binding:"required"`
+	BudgetMin  int    `json:"budget_min" binding:"min=0"`
+	BudgetMax  int    `json:"budget_max" binding:"required,gtefield=BudgetMin"`
+	Currency   string `json:"currency" binding:"required,len=3,alpha"`
+	Timeline   string `json:"timeline" binding:"required,max=255"`
+}
+
+// RespondInquiryRequest answers an inquiry. A counter needs the full set of
+// proposed terms.
+type RespondInquiryRequest struct {
+	Action    string `json:"action" binding:"required,oneof=accept decline counter withdraw"`
+	Message   string `json:"message"`
+	BudgetMin *int   `json:"budget_min" binding:"omitempty,min=0"`
+	BudgetMax *int   `json:"budget_max" binding:"omitempty,min=0"`
+	Timeline  string `json:"timeline" binding:"max=255"`
+}
+
+// GigRequest creates a gig or replaces its details
+type GigRequest struct {
+	Title       string    `json:"title" binding:"required,max=255"`
+	Description string    `json:"description" binding:"required"`
+	CategoryID  uint      `json:"category_id" binding:"required"`
+	BudgetMin   int       `json:"budget_min" binding:"min=0"`
+	BudgetMax   int       `json:"budget_max" binding:"required,gtefield=BudgetMin"`
+	Currency    string    `json:"currency" binding:"required,len=3,alpha"`
+	Deadline    time.Time `json:"deadline" binding:"required"`
+	Status      string    `json:"status" binding:"omitempty,oneof=open closed"`
+}
+
+type ApplyGigRequest struct {
+	CoverNote  string `json:"cover_note" binding:"max=5000"`
+	ProjectIDs []uint `json:"project_ids" binding:"required,min=1,max=10"` // Work samples from the applicant's portfolio
+}
+
+type GigApplicationStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=submitted shortlisted accepted rejected"`
+}
+
+// AvailabilityRequest adds or changes an availability window. Dates are
+// YYYY-MM-DD and the end date is inclusive.
+type AvailabilityRequest struct {
+	StartDate string `json:"start_date" binding:"required,datetime=2006-01-02"`
+	EndDate   string `json:"end_date" binding:"required,datetime=2006-01-02"`
+	Status    string `json:"status" binding:"required,oneof=available booked"`
+	Note      string `json:"note" binding:"max=255"`
+}
+
+type RateCardRequest struct {
+	Unit        string `json:"unit" binding:"required,oneof=hourly day project"`
+	Amount      int    `json:"amount" binding:"required,min=1"`
+	Currency    string `json:"currency" binding:"required,len=3,alpha"`
+	Description string `json:"description" binding:"max=255"`
+}
+
+// SetRatesRequest replaces all of the user's rate cards; an empty list
+// removes them
+type SetRatesRequest struct {
+	Rates []RateCardRequest `json:"rates" binding:"required,max=20,dive"`
+}
+
 // Response models
 type UserResponse struct {
-	ID        uint             `json:"id"`
-	Name      string           `json:"name"`
-	Email     string           `json:"email"`
-	Role      string           `json:"role"`
-	Bio       string           `json:"bio"`
-	Location  string           `json:"location"`
-	Skills    string           `json:"skills"`
-	Website   string           `json:"website"`
-	AvatarURL string           `json:"avatar_url"`
-	ForHire   bool             `json:"for_hire"`
-	Company   *CompanyResponse `json:"company,omitempty"`
-	CreatedAt time.Time        `json:"created_at"`
+	ID                  uint             `json:"id"`
+	Username            string           `json:"username,omitempty"`
+	Name                string           `json:"name"`
+	Email               string           `json:"email"`
+	Role                string           `json:"role"`
+	Bio                 string           `json:"bio"`
+	Location            string           `json:"location"`
+	Skills              []string         `json:"skills,omitempty"`
+	Website             string           `json:"website"`
+	BehanceURL          string           `json:"behance_url"`
+	DribbbleURL         string           `json:"dribbble_url"`
+	LinkedInURL         string           `json:"linkedin_url"`
+	TwitterURL          string           `json:"twitter_url"`
+	AvatarURL           string           `json:"avatar_url"`
+	ForHire             bool             `json:"for_hire"`
+	Verified            bool             `json:"verified"`
+	Status              string           `json:"status,omitempty"`
+	DeletionScheduledAt *time.Time       `json:"deletion_scheduled_at,omitempty"`
+	Company             *CompanyResponse `json:"company,omitempty"`
+	CreatedAt           time.Time        `json:"created_at"`
 }
 
 type CompanyResponse struct {
-	ID          uint   `json:"id"`
-	Name        string `json:"name"`
+	ID          uint           `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Website     string         `json:"website"`
+	Location    string         `json:"location"`
+	LogoURL     string         `json:"logo_url"`
+	Industry    string         `json:"industry"`
+	Size        string         `json:"size"`
+	OwnerID     *uint          `json:"owner_id,omitempty"`
+	Members     []UserResponse `json:"members,omitempty"` // Team, on the company page
+}
+
+type CompanyInvitationResponse struct {
+	ID        uint         `json:"id"`
+	Email     string       `json:"email"`
+	InvitedBy UserResponse `json:"invited_by"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// PublicProfileResponse is a user's profile as anyone can see it. Email is
+// only filled in for the owner.
+type PublicProfileResponse struct {
+	ID             uint                   `json:"id"`
+	Username       string                 `json:"username,omitempty"`
+	Name           string                 `json:"name"`
+	Email          string                 `json:"email,omitempty"`
+	Role           string                 `json:"role"`
+	Bio            string                 `json:"bio"`
+	Location       string                 `json:"location"`
+	Skills         []string               `json:"skills"`
+	Website        string                 `json:"website"`
+	BehanceURL     string                 `json:"behance_url"`
+	DribbbleURL    string                 `json:"dribbble_url"`
+	LinkedInURL    string                 `json:"linkedin_url"`
+	TwitterURL     string                 `json:"twitter_url"`
+	AvatarURL      string                 `json:"avatar_url"`
+	ForHire        bool                   `json:"for_hire"`
+	Availability   []AvailabilityResponse `json:"availability"` // Upcoming windows only
+	Rates          []RateCardResponse     `json:"rates"`
+	FollowersCount int64                  `json:"followers_count"`
+	FollowingCount int64                  `json:"following_count"`
+	ProjectsCount  int64                  `json:"projects_count"`
+	IsFollowing    bool                   `json:"is_following"` // If current user follows them
+	CreatedAt      time.Time              `json:"created_at"`
+}
+
+// TalentResponse is one creative in talent search results
+type TalentResponse struct {
+	ID             uint               `json:"id"`
+	Username       string             `json:"username,omitempty"`
+	Name           string             `json:"name"`
+	Bio            string             `json:"bio"`
+	Location       string             `json:"location"`
+	Skills         []string           `json:"skills"`
+	AvatarURL      string             `json:"avatar_url"`
+	ForHire        bool               `json:"for_hire"`
+	Rates          []RateCardResponse `json:"rates"`
+	FollowersCount int64              `json:"followers_count"`
+	ProjectsCount  int64              `json:"projects_count"`
+	TotalLikes     int64              `json:"total_likes"`
+	TotalViews     int64              `json:"total_views"`
+	LastActiveAt   *time.Time         `json:"last_active_at"`
+}
+
+// Availability and rates
+type AvailabilityResponse struct {
+	ID        uint   `json:"id"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	Status    string `json:"status"`
+	Note      string `json:"note"`
+}
+
+type RateCardResponse struct {
+	Unit        string `json:"unit"`
+	Amount      int    `json:"amount"`
+	Currency    string `json:"currency"`
 	Description string `json:"description"`
-	Website     string `json:"website"`
-	Location    string `json:"location"`
-	LogoURL     string `json:"logo_url"`
-	Industry    string `json:"industry"`
-	Size        string `json:"size"`
 }
 
 type ProjectResponse struct {
@@ -83,7 +380,10 @@ type ProjectResponse struct {
 	Tags        string                 `json:"tags"`
 	Views       int                    `json:"views"`
 	LikesCount  int                    `json:"likes_count"`
-	IsLiked     bool                   `json:"is_liked"` // If current user liked it
+	Featured    bool                   `json:"featured"`
+	IsLiked     bool                   `json:"is_liked"`         // If current user liked it
+	Status      string                 `json:"status,omitempty"` // Only shown to the owner
+	PublishAt   *time.Time             `json:"publish_at,omitempty"`
 	CreatedAt   time.Time              `json:"created_at"`
 }
 
@@ -112,3 +412,243 @@ type LikeResponse struct {
 	User      UserResponse `json:"user"`
 	CreatedAt time.Time    `json:"created_at"`
 }
+
+type IdentityResponse struct {
+	ID        uint      `json:"id"`
+	Provider  string    `json:"provider"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type APIKeyResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type DailyCount struct {
+	Day   string `json:"day"`
+	Count int64  `json:"count"`
+}
+
+type NamedCount struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+type AccountStatusChangeResponse struct {
+	ID             uint         `json:"id"`
+	Status         string       `json:"status"`
+	Reason         string       `json:"reason"`
+	SuspendedUntil *time.Time   `json:"suspended_until"`
+	Actor          UserResponse `json:"actor"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+type AuditEventResponse struct {
+	ID         uint            `json:"id"`
+	ActorID    *uint           `json:"actor_id"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   string          `json:"target_id"`
+	IPAddress  string          `json:"ip_address"`
+	UserAgent  string          `json:"user_agent"`
+	Changes    json.RawMessage `json:"changes"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// Personal data export
+type DataExport struct {
+	ExportedAt   time.Time              `json:"exported_at"`
+	Profile      ExportProfile          `json:"profile"`
+	Availability []AvailabilityResponse `json:"availability"`
+	Rates        []RateCardResponse     `json:"rates"`
+	Projects     []ExportProject        `json:"projects"`
+	Comments     []ExportComment        `json:"comments"`
+	Likes        []ExportLike           `json:"likes"`
+	Followers    []ExportFollow         `json:"followers"`
+	Following    []ExportFollow         `json:"following"`
+	Inquiries    []InquiryResponse      `json:"inquiries"` // Hire inquiries received, with their history
+	Applications []ExportGigApplication `json:"gig_applications"`
+}
+
+type ExportProfile struct {
+	ID          uint      `json:"id"`
+	Username    string    `json:"username,omitempty"`
+	Name        string    `json:"name"`
+	Email       string    `json:"email"`
+	Role        string    `json:"role"`
+	Bio         string    `json:"bio"`
+	Location    string    `json:"location"`
+	Skills      string    `json:"skills"`
+	Website     string    `json:"website"`
+	BehanceURL  string    `json:"behance_url"`
+	DribbbleURL string    `json:"dribbble_url"`
+	LinkedInURL string    `json:"linkedin_url"`
+	TwitterURL  string    `json:"twitter_url"`
+	AvatarURL   string    `json:"avatar_url"`
+	ForHire     bool      `json:"for_hire"`
+	Verified    bool      `json:"verified"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type ExportProject struct {
+	ID          uint       `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Category    string     `json:"category"`
+	Tags        string     `json:"tags"`
+	CoverImage  string     `json:"cover_image"`
+	ImageURLs   []string   `json:"image_urls"`
+	Views       int        `json:"views"`
+	LikesCount  int        `json:"likes_count"`
+	Status      string     `json:"status"`
+	PublishAt   *time.Time `json:"publish_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+type ExportComment struct {
+	ID        uint      `json:"id"`
+	ProjectID uint      `json:"project_id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ExportLike struct {
+	ProjectID uint      `json:"project_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ExportFollow struct {
+	UserID    uint      `json:"user_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ExportGigApplication struct {
+	ID         uint      `json:"id"`
+	GigID      uint      `json:"gig_id"`
+	GigTitle   string    `json:"gig_title"`
+	Company    string    `json:"company"`
+	CoverNote  string    `json:"cover_note"`
+	Status     string    `json:"status"`
+	ProjectIDs []uint    `json:"project_ids"` // Attached work, in the order it was picked
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Shortlists
+type ShortlistResponse struct {
+	ID              uint                         `json:"id"`
+	Name            string                       `json:"name"`
+	Description     string                       `json:"description"`
+	CreatedBy       UserResponse                 `json:"created_by"`
+	CandidatesCount int                          `json:"candidates_count"`
+	Candidates      []ShortlistCandidateResponse `json:"candidates,omitempty"`
+	CreatedAt       time.Time                    `json:"created_at"`
+	UpdatedAt       time.Time                    `json:"updated_at"`
+}
+
+type ShortlistCandidateResponse struct {
+	Candidate UserResponse            `json:"candidate"`
+	Status    string                  `json:"status"`
+	AddedBy   UserResponse            `json:"added_by"`
+	Notes     []ShortlistNoteResponse `json:"notes"`
+	CreatedAt time.Time               `json:"created_at"`
+	UpdatedAt time.Time               `json:"updated_at"`
+}
+
+type ShortlistNoteResponse struct {
+	ID        uint         `json:"id"`
+	Author    UserResponse `json:"author"`
+	Content   string       `json:"content"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// Hire inquiries
+type InquiryResponse struct {
+	ID        uint                   `json:"id"`
+	Company   CompanyResponse        `json:"company"`
+	Sender    UserResponse           `json:"sender"`
+	Creative  UserResponse           `json:"creative"`
+	Title     string                 `json:"title"`
+	Brief     string                 `json:"brief"`
+	BudgetMin int                    `json:"budget_min"`
+	BudgetMax int                    `json:"budget_max"`
+	Currency  string                 `json:"currency"`
+	Timeline  string                 `json:"timeline"`
+	Status    string                 `json:"status"`
+	History   []InquiryEventResponse `json:"history,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
+}
+
+type InquiryEventResponse struct {
+	ID        uint         `json:"id"`
+	Actor     UserResponse `json:"actor"`
+	Status    string       `json:"status"`
+	Message   string       `json:"message"`
+	BudgetMin *int         `json:"budget_min,omitempty"`
+	BudgetMax *int         `json:"budget_max,omitempty"`
+	Timeline  string       `json:"timeline,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// Gig board
+type GigResponse struct {
+	ID                uint             `json:"id"`
+	Company           CompanyResponse  `json:"company"`
+	PostedBy          UserResponse     `json:"posted_by"`
+	Category          CategoryResponse `json:"category"`
+	Title             string           `json:"title"`
+	Description       string           `json:"description"`
+	BudgetMin         int              `json:"budget_min"`
+	BudgetMax         int              `json:"budget_max"`
+	Currency          string           `json:"currency"`
+	Deadline          time.Time        `json:"deadline"`
+	Status            string           `json:"status"`
+	ApplicationsCount int64            `json:"applications_count"`
+	CreatedAt         time.Time        `json:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at"`
+}
+
+type GigApplicationResponse struct {
+	ID        uint              `json:"id"`
+	Gig       *GigResponse      `json:"gig,omitempty"` // Set when listing the applicant's own applications
+	Applicant UserResponse      `json:"applicant"`
+	CoverNote string            `json:"cover_note"`
+	Status    string            `json:"status"`
+	Projects  []ProjectResponse `json:"projects"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// Project revisions
+type RevisionResponse struct {
+	Number       int          `json:"number"`
+	Author       UserResponse `json:"author"`
+	Changed      []string     `json:"changed"` // Fields that differ from the previous revision
+	RestoredFrom *int         `json:"restored_from,omitempty"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+// RevisionChange is one field's change between two revisions. For images the
+// added and removed URLs are listed too.
+type RevisionChange struct {
+	From    interface{} `json:"from"`
+	To      interface{} `json:"to"`
+	Added   []string    `json:"added,omitempty"`
+	Removed []string    `json:"removed,omitempty"`
+}
+
+type RevisionDiffResponse struct {
+	From    int                       `json:"from"`
+	To      int                       `json:"to"`
+	Changes map[string]RevisionChange `json:"changes"`
+}
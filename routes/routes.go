@@ -3,16 +3,35 @@ package routes
 import (
 	"jobconnect-backend/handlers"
 	"jobconnect-backend/middleware"
+	"jobconnect-backend/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
 func SetupRoutes(r *gin.Engine) {
+	// Public signing keys for services that verify our tokens
+	r.GET("/.well-known/jwks.json", handlers.GetJWKS)
+
 	// Auth routes (public)
 	auth := r.Group("/api/auth")
 	{
 		auth.POST("/register", handlers.RegisterUser)
 		auth.POST("/login", handlers.LoginUser)
+		auth.POST("/refresh", handlers.RefreshToken)
+		auth.POST("/logout", handlers.Logout)
+		auth.POST("/logout-all", middleware.AuthMiddleware(), middleware.BlockImpersonation(), handlers.LogoutAll)
+		auth.POST("/verify", handlers.VerifyEmail)
+		auth.POST("/verify/resend", handlers.ResendVerification)
+		auth.POST("/forgot-password", handlers.ForgotPassword)
+		auth.POST("/reset-password", handlers.ResetPassword)
+		auth.POST("/2fa/verify", handlers.VerifyTwoFactorLogin)
+		auth.POST("/2fa/enroll", handlers.EnrollTwoFactorChallenge)
+		auth.POST("/2fa/enroll/confirm", handlers.ConfirmTwoFactorChallenge)
+
+		// Social login
+		auth.GET("/oidc/:provider", handlers.StartOIDCLogin)
+		auth.POST("/oidc/:provider/callback", handlers.OIDCCallback)
+		auth.POST("/oidc/:provider/link", middleware.AuthMiddleware(), middleware.BlockImpersonation(), handlers.LinkOIDCIdentity)
 	}
 
 	// Public routes
@@ -20,7 +39,7 @@ func SetupRoutes(r *gin.Engine) {
 	{
 		// Browse projects
 		public.GET("/projects", handlers.GetProjects)
-		public.GET("/projects/:id", handlers.GetProject)
+		public.GET("/projects/:id", middleware.OptionalAuthMiddleware(utils.ScopeProjectsRead), handlers.GetProject) // Owners also see their unpublished work
 
 		// Categories
 		public.GET("/categories", handlers.GetCategories)
@@ -29,38 +48,185 @@ func SetupRoutes(r *gin.Engine) {
 		public.GET("/projects/:id/likes", handlers.GetProjectLikes)
 		public.GET("/projects/:id/comments", handlers.GetProjectComments)
 
+		// User profiles and portfolios (public view, email shown to the owner)
+		public.GET("/users/:id", middleware.OptionalAuthMiddleware(utils.ScopeProfileRead), handlers.GetUserProfile)
+		public.GET("/users/:id/projects", handlers.GetUserProjects)
+
+		// Company pages
+		public.GET("/companies/:id", handlers.GetCompanyPage)
+
+		// Gig board
+		public.GET("/gigs", handlers.GetGigs)
+		public.GET("/gigs/:id", handlers.GetGig)
+
+		// Vanity profile URLs
+		public.GET("/u/:handle", middleware.OptionalAuthMiddleware(utils.ScopeProfileRead), handlers.GetUserByHandle)
+		public.GET("/handles/:handle/available", middleware.OptionalAuthMiddleware(utils.ScopeProfileRead), handlers.CheckHandleAvailability)
+
 		// User followers/following (public view)
 		public.GET("/users/:id/followers", handlers.GetUserFollowers)
 		public.GET("/users/:id/following", handlers.GetUserFollowing)
 	}
 
-	// Protected routes (requires authentication)
+	// Protected routes (requires authentication, JWT only)
 	protected := r.Group("/api")
 	protected.Use(middleware.AuthMiddleware())
 	{
 		// Profile
-		protected.GET("/profile", handlers.GetProfile)
-		protected.PUT("/profile", handlers.UpdateProfile)
+		protected.PATCH("/profile", handlers.UpdateProfile)
+		protected.PUT("/profile", handlers.UpdateProfile) // Kept for older clients, same partial semantics
+		protected.PUT("/profile/handle", middleware.BlockImpersonation(), handlers.SetHandle) // Changes the user's public URL
+		protected.PUT("/profile/password", middleware.BlockImpersonation(), handlers.ChangePassword)
+
+		// Two-factor authentication
+		protected.POST("/2fa/setup", middleware.BlockImpersonation(), handlers.SetupTwoFactor)
+		protected.POST("/2fa/confirm", middleware.BlockImpersonation(), handlers.ConfirmTwoFactor)
+		protected.POST("/2fa/disable", middleware.BlockImpersonation(), handlers.DisableTwoFactor)
+		protected.POST("/2fa/recovery-codes", middleware.BlockImpersonation(), handlers.RegenerateRecoveryCodes)
+
+		// Data export and account deletion
+		protected.GET("/profile/export", middleware.BlockImpersonation(), handlers.ExportMyData)
+		protected.DELETE("/profile", middleware.BlockImpersonation(), handlers.RequestAccountDeletion)
+		protected.POST("/profile/confirm-deletion", middleware.BlockImpersonation(), handlers.ConfirmAccountDeletion)
+		protected.POST("/profile/cancel-deletion", middleware.BlockImpersonation(), handlers.CancelAccountDeletion)
+
+		// Availability calendar and rate cards
+		protected.GET("/profile/availability", handlers.GetMyAvailability)
+		protected.POST("/profile/availability", handlers.AddAvailability)
+		protected.PUT("/profile/availability/:id", handlers.UpdateAvailability)
+		protected.DELETE("/profile/availability/:id", handlers.DeleteAvailability)
+		protected.GET("/profile/rates", handlers.GetMyRates)
+		protected.PUT("/profile/rates", handlers.SetRates)
+
+		// Linked external accounts
+		protected.GET("/profile/identities", handlers.GetMyIdentities)
+		protected.DELETE("/profile/identities/:id", middleware.BlockImpersonation(), handlers.UnlinkIdentity)
+
+		// Personal API keys
+		protected.POST("/api-keys", middleware.BlockImpersonation(), handlers.CreateAPIKey)
+		protected.GET("/api-keys", handlers.GetMyAPIKeys)
+		protected.DELETE("/api-keys/:id", middleware.BlockImpersonation(), handlers.RevokeAPIKey)
+
+		// Support impersonation
+		protected.POST("/impersonation/end", handlers.EndImpersonation)
 
 		// Upload
-		protected.POST("/upload/image", handlers.UploadImage)
-		protected.POST("/upload/images", handlers.UploadMultipleImages)
 		protected.POST("/upload/avatar", handlers.UploadAvatar)
 
+		// Company accounts
+		companyManage := middleware.RequirePermission(utils.PermCompanyManage)
+		protected.POST("/company", companyManage, handlers.CreateCompany)
+		protected.GET("/company", companyManage, handlers.GetMyCompany)
+		protected.PATCH("/company", companyManage, handlers.UpdateCompany)
+		protected.PUT("/company", companyManage, handlers.UpdateCompany) // Kept for older clients, same partial semantics
+		protected.POST("/company/logo", companyManage, handlers.UploadCompanyLogo)
+		protected.POST("/company/invitations", companyManage, handlers.InviteRecruiter)
+		protected.GET("/company/invitations", companyManage, handlers.GetCompanyInvitations)
+		protected.DELETE("/company/invitations/:id", companyManage, handlers.RevokeCompanyInvitation)
+		protected.POST("/company/invitations/accept", companyManage, handlers.AcceptCompanyInvitation) // Creatives can't join a company
+		protected.DELETE("/company/members/:id", companyManage, middleware.BlockImpersonation(), handlers.RemoveCompanyMember)
+
+		// Talent search
+		protected.GET("/talent", middleware.RequirePermission(utils.PermTalentSearch), handlers.SearchTalent)
+
+		// Recruiter shortlists (company members only)
+		shortlistManage := middleware.RequirePermission(utils.PermShortlistManage)
+		protected.GET("/shortlists", shortlistManage, handlers.GetShortlists)
+		protected.POST("/shortlists", shortlistManage, handlers.CreateShortlist)
+		protected.GET("/shortlists/:id", shortlistManage, handlers.GetShortlist)
+		protected.PUT("/shortlists/:id", shortlistManage, handlers.UpdateShortlist)
+		protected.DELETE("/shortlists/:id", shortlistManage, handlers.DeleteShortlist)
+		protected.POST("/shortlists/:id/candidates", shortlistManage, handlers.AddShortlistCandidate)
+		protected.PUT("/shortlists/:id/candidates/:userId", shortlistManage, handlers.UpdateShortlistCandidate)
+		protected.DELETE("/shortlists/:id/candidates/:userId", shortlistManage, handlers.RemoveShortlistCandidate)
+		protected.POST("/shortlists/:id/candidates/:userId/notes", shortlistManage, handlers.AddShortlistNote)
+		protected.DELETE("/shortlists/:id/notes/:noteId", shortlistManage, handlers.DeleteShortlistNote)
+
+		// Hire inquiries
+		protected.POST("/inquiries", middleware.RequirePermission(utils.PermInquirySend), handlers.SendInquiry)
+		protected.GET("/inquiries", handlers.GetInquiries)
+		protected.GET("/inquiries/:id", handlers.GetInquiry)
+		protected.POST("/inquiries/:id/respond", handlers.RespondToInquiry)
+
+		// Gig board: companies post gigs and review applications, creatives apply
+		gigManage := middleware.RequirePermission(utils.PermGigManage)
+		protected.POST("/gigs", gigManage, handlers.CreateGig)
+		protected.GET("/company/gigs", gigManage, handlers.GetCompanyGigs)
+		protected.PUT("/gigs/:id", gigManage, handlers.UpdateGig)
+		protected.DELETE("/gigs/:id", gigManage, handlers.DeleteGig)
+		protected.GET("/gigs/:id/applications", gigManage, handlers.GetGigApplications)
+		protected.PUT("/gigs/:id/applications/:applicationId", gigManage, handlers.UpdateGigApplication)
+		protected.POST("/gigs/:id/apply", middleware.RequirePermission(utils.PermGigApply), handlers.ApplyToGig)
+		protected.DELETE("/gigs/:id/apply", handlers.WithdrawGigApplication)
+		protected.GET("/my-gig-applications", handlers.GetMyGigApplications)
+	}
+
+	// Protected routes that also accept personal API keys with the given scope
+	scoped := r.Group("/api")
+	{
+		profileRead := middleware.AuthMiddleware(utils.ScopeProfileRead)
+		projectsRead := middleware.AuthMiddleware(utils.ScopeProjectsRead)
+		projectsWrite := middleware.AuthMiddleware(utils.ScopeProjectsWrite)
+		socialWrite := middleware.AuthMiddleware(utils.ScopeSocialWrite)
+
+		// Profile
+		scoped.GET("/profile", profileRead, handlers.GetProfile)
+
+		// Upload
+		scoped.POST("/upload/image", projectsWrite, handlers.UploadImage)
+		scoped.POST("/upload/images", projectsWrite, handlers.UploadMultipleImages)
+
 		// Projects
-		protected.POST("/projects", handlers.CreateProject)
-		protected.GET("/my-projects", handlers.GetMyProjects)
-		protected.PUT("/projects/:id", handlers.UpdateProject)
-		protected.DELETE("/projects/:id", handlers.DeleteProject)
+		scoped.POST("/projects", projectsWrite, middleware.VerifiedMiddleware(), middleware.RequirePermission(utils.PermProjectCreate), handlers.CreateProject)
+		scoped.GET("/my-projects", projectsRead, handlers.GetMyProjects)
+		scoped.PUT("/projects/:id", projectsWrite, handlers.UpdateProject)
+		scoped.PUT("/projects/:id/status", projectsWrite, handlers.UpdateProjectStatus)
+		scoped.GET("/projects/:id/revisions", projectsRead, handlers.GetProjectRevisions)
+		scoped.GET("/projects/:id/revisions/diff", projectsRead, handlers.GetProjectRevisionDiff)
+		scoped.POST("/projects/:id/revisions/:number/rollback", projectsWrite, handlers.RollbackProject)
+		scoped.DELETE("/projects/:id", projectsWrite, middleware.BlockImpersonation(), handlers.DeleteProject)
 
 		// Social features
-		protected.POST("/projects/:id/like", handlers.LikeProject)
-		protected.DELETE("/projects/:id/unlike", handlers.UnlikeProject)
-		protected.POST("/projects/:id/comments", handlers.AddComment)
-		protected.DELETE("/comments/:id", handlers.DeleteComment)
+		scoped.POST("/projects/:id/like", socialWrite, handlers.LikeProject)
+		scoped.DELETE("/projects/:id/unlike", socialWrite, handlers.UnlikeProject)
+		scoped.POST("/projects/:id/comments", socialWrite, middleware.VerifiedMiddleware(), middleware.RequirePermission(utils.PermCommentCreate), handlers.AddComment)
+		scoped.DELETE("/comments/:id", socialWrite, middleware.BlockImpersonation(), handlers.DeleteComment)
 
 		// Follow/Unfollow
-		protected.POST("/users/:id/follow", handlers.FollowUser)
-		protected.DELETE("/users/:id/unfollow", handlers.UnfollowUser)
+		scoped.POST("/users/:id/follow", socialWrite, handlers.FollowUser)
+		scoped.DELETE("/users/:id/unfollow", socialWrite, handlers.UnfollowUser)
+	}
+
+	// Admin routes, each guarded by the permission it needs
+	admin := r.Group("/api/admin")
+	admin.Use(middleware.AuthMiddleware())
+	{
+		// Users
+		admin.GET("/users", middleware.RequirePermission(utils.PermUserManage), handlers.AdminGetUsers)
+		admin.PUT("/users/:id/role", middleware.RequirePermission(utils.PermUserManage), handlers.ChangeUserRole)
+		admin.POST("/users/:id/unlock", middleware.RequirePermission(utils.PermUserManage), handlers.UnlockUser)
+		admin.PUT("/users/:id/require-2fa", middleware.RequirePermission(utils.PermUserManage), handlers.RequireTwoFactor)
+		admin.POST("/users/:id/suspend", middleware.RequirePermission(utils.PermUserBan), handlers.SuspendUser)
+		admin.POST("/users/:id/ban", middleware.RequirePermission(utils.PermUserBan), handlers.BanUser)
+		admin.POST("/users/:id/reinstate", middleware.RequirePermission(utils.PermUserBan), handlers.ReinstateUser)
+		admin.GET("/users/:id/status-history", middleware.RequirePermission(utils.PermUserBan), handlers.GetUserStatusHistory)
+		admin.POST("/users/:id/impersonate", middleware.RequirePermission(utils.PermUserImpersonate), middleware.BlockImpersonation(), handlers.ImpersonateUser)
+
+		// Content moderation
+		admin.PUT("/projects/:id/feature", middleware.RequirePermission(utils.PermProjectFeature), handlers.FeatureProject)
+		admin.DELETE("/projects/:id", middleware.RequirePermission(utils.PermProjectDeleteAny), handlers.AdminDeleteProject)
+		admin.POST("/projects/:id/restore", middleware.RequirePermission(utils.PermProjectDeleteAny), handlers.RestoreProject)
+		admin.DELETE("/comments/:id", middleware.RequirePermission(utils.PermCommentModerate), handlers.AdminDeleteComment)
+
+		// Dashboard
+		admin.GET("/stats", middleware.RequirePermission(utils.PermStatsView), handlers.GetDashboardStats)
+
+		// Audit log
+		admin.GET("/audit-events", middleware.RequirePermission(utils.PermAuditView), handlers.GetAuditEvents)
+		admin.GET("/audit-events/export", middleware.RequirePermission(utils.PermAuditView), handlers.ExportAuditEvents)
+
+		// Role permissions
+		admin.GET("/permissions", middleware.RequirePermission(utils.PermRoleManage), handlers.GetRolePermissions)
+		admin.PUT("/roles/:role/permissions", middleware.RequirePermission(utils.PermRoleManage), handlers.SetRolePermissions)
 	}
 }
@@ -0,0 +1,31 @@
+package utils
+
+import "time"
+
+// Account statuses
+const (
+	StatusActive    = "active"
+	StatusSuspended = "suspended"
+	StatusBanned    = "banned"
+	StatusDeleted   = "deleted"
+)
+
+// AccountBlocked reports whether an account status currently denies access.
+// Suspensions lapse on their own once suspendedUntil has passed.
+func AccountBlocked(status string, suspendedUntil *time.Time) bool {
+	switch status {
+	case StatusBanned, StatusDeleted:
+		return true
+	case StatusSuspended:
+		return suspendedUntil == nil || time.Now().Before(*suspendedUntil)
+	}
+	return false
+}
+
+// AccountBlockedMessage is the error shown to a blocked user
+func AccountBlockedMessage(status string, suspendedUntil *time.Time) string {
+	if status == StatusSuspended && suspendedUntil != nil {
+		return "This account is suspended until " + suspendedUntil.UTC().Format(time.RFC1123)
+	}
+	return "This account has been " + status
+}
@@ -0,0 +1,42 @@
+package utils
+
+import "strings"
+
+// APIKeyPrefix marks personal API keys so they can be told apart from JWTs
+const APIKeyPrefix = "rag_"
+
+// Scopes that can be granted to a personal API key
+const (
+	ScopeProfileRead   = "profile:read"
+	ScopeProjectsRead  = "projects:read"
+	ScopeProjectsWrite = "projects:write"
+	ScopeSocialWrite   = "social:write"
+)
+
+var validScopes = map[string]bool{
+	ScopeProfileRead:   true,
+	ScopeProjectsRead:  true,
+	ScopeProjectsWrite: true,
+	ScopeSocialWrite:   true,
+}
+
+// ValidScope reports whether scope is a known API key scope
+func ValidScope(scope string) bool {
+	return validScopes[scope]
+}
+
+// GenerateAPIKey returns a new raw API key and the short prefix shown in
+// listings so users can tell their keys apart
+func GenerateAPIKey() (key string, displayPrefix string, err error) {
+	token, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", "", err
+	}
+	key = APIKeyPrefix + token
+	return key, key[:len(APIKeyPrefix)+6], nil
+}
+
+// IsAPIKey reports whether a bearer credential is an API key rather than a JWT
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, APIKeyPrefix)
+}
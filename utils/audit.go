@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"jobconnect-backend/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditChange is one field's before/after value in an audit diff
+type AuditChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// AuditDiff maps field names to their change
+type AuditDiff map[string]AuditChange
+
+// RecordAudit appends an audit event attributed to the authenticated user.
+// While impersonating, the admin is the actor and the impersonated user is
+// added to the diff.
+func RecordAudit(c *gin.Context, action, targetType string, targetID interface{}, diff AuditDiff) {
+	var actorID *uint
+	if userID, ok := c.Get("user_id"); ok {
+		if id, ok := userID.(uint); ok {
+			actorID = &id
+		}
+	}
+	if impersonatorID, ok := c.Get("impersonator_id"); ok {
+		if id, ok := impersonatorID.(uint); ok {
+			if diff == nil {
+				diff = AuditDiff{}
+			}
+			diff["impersonated_user_id"] = AuditChange{From: nil, To: *actorID}
+			actorID = &id
+		}
+	}
+	writeAudit(c.ClientIP(), c.Request.UserAgent(), actorID, action, targetType, targetID, diff)
+}
+
+// RecordAuditAs appends an audit event for an explicit actor, for requests
+// that aren't authenticated yet (login, registration). A zero actorID means
+// the actor is unknown.
+func RecordAuditAs(c *gin.Context, actorID uint, action, targetType string, targetID interface{}, diff AuditDiff) {
+	var actor *uint
+	if actorID != 0 {
+		actor = &actorID
+	}
+	writeAudit(c.ClientIP(), c.Request.UserAgent(), actor, action, targetType, targetID, diff)
+}
+
+// RecordSystemAudit appends an audit event for work done by the server itself,
+// such as background jobs, outside of any request
+func RecordSystemAudit(action, targetType string, targetID interface{}, diff AuditDiff) {
+	writeAudit("", "", nil, action, targetType, targetID, diff)
+}
+
+func writeAudit(ipAddress, userAgent string, actorID *uint, action, targetType string, targetID interface{}, diff AuditDiff) {
+	changes := "{}"
+	if len(diff) > 0 {
+		if b, err := json.Marshal(diff); err == nil {
+			changes = string(b)
+		}
+	}
+
+	event := config.AuditEvent{
+		ActorID:    actorID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   fmt.Sprint(targetID),
+		IPAddress:  ipAddress,
+		UserAgent:  userAgent,
+		Changes:    changes,
+	}
+
+	// Auditing must never break the action being audited
+	if err := config.DB.Create(&event).Error; err != nil {
+		log.Println("Warning: Failed to write audit event:", err)
+	}
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// Handles are 3-30 letters, digits, underscores or hyphens and must start and
+// end with a letter or digit
+var handlePattern = regexp.MustCompile(`^[a-zA-Z0-9](?:[a-zA-Z0-9_-]{1,28}[a-zA-Z0-9])$`)
+
+// reservedHandles can't be claimed because they collide with routes, look
+// official, or would confuse other users
+var reservedHandles = map[string]bool{
+	"about": true, "account": true, "admin": true, "administrator": true,
+	"api": true, "auth": true, "categories": true, "company": true,
+	"companies": true, "contact": true, "dashboard": true, "explore": true,
+	"help": true, "home": true, "login": true, "logout": true,
+	"me": true, "moderator": true, "null": true, "profile": true,
+	"projects": true, "register": true, "root": true, "rosyartgrid": true,
+	"search": true, "security": true, "settings": true, "signup": true,
+	"staff": true, "support": true, "system": true, "u": true,
+	"undefined": true, "user": true, "users": true,
+}
+
+var (
+	ErrHandleFormat   = errors.New("handle must be 3-30 letters, digits, underscores or hyphens, starting and ending with a letter or digit")
+	ErrHandleReserved = errors.New("handle is reserved")
+)
+
+// NormalizeHandle is the case-insensitive form handles are compared by
+func NormalizeHandle(handle string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(handle), "@"))
+}
+
+// ValidateHandle checks a handle's format and that it isn't reserved
+func ValidateHandle(handle string) error {
+	if !handlePattern.MatchString(handle) {
+		return ErrHandleFormat
+	}
+	if reservedHandles[NormalizeHandle(handle)] {
+		return ErrHandleReserved
+	}
+	return nil
+}
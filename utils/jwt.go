@@ -1,6 +1,10 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"os"
 	"time"
@@ -8,32 +12,83 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+const (
+	// AccessTokenTTL is kept short because access tokens are only revocable
+	// through their session, which is checked on every request.
+	AccessTokenTTL = 15 * time.Minute
+	// RefreshTokenTTL is how long a session stays usable without a refresh.
+	RefreshTokenTTL = 30 * 24 * time.Hour
+	// ImpersonationTTL bounds support sessions; they can't be refreshed.
+	ImpersonationTTL = 30 * time.Minute
+)
+
+// Issuer is the iss claim on every token, so partner services can tell
+// RosyArtGrid tokens apart from their own
+func Issuer() string {
+	if iss := os.Getenv("JWT_ISSUER"); iss != "" {
+		return iss
+	}
+	return "rosyartgrid"
+}
+
+// AudienceAccess is the aud of access tokens. Services verifying our tokens
+// against the JWKS must require it: action tokens are signed with the same
+// keys but carry an "action:<purpose>" audience instead.
+const AudienceAccess = "access"
+
+func actionAudience(purpose string) string {
+	return "action:" + purpose
+}
+
 type Claims struct {
-	UserID uint   `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID    uint   `json:"user_id"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	SessionID uint   `json:"sid"`
+	// ImpersonatorID is the admin acting as UserID, set only on impersonation tokens
+	ImpersonatorID uint `json:"impersonator_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID uint, email string, role string) (string, error) {
+func GenerateToken(userID uint, email string, role string, sessionID uint) (string, error) {
+	claims := Claims{
+		UserID:    userID,
+		Email:     email,
+		Role:      role,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    Issuer(),
+			Audience:  jwt.ClaimStrings{AudienceAccess},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(AccessTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	return signClaims(claims)
+}
+
+// GenerateImpersonationToken issues an access token that lets an admin act as
+// another user. It carries both IDs so every request stays attributable.
+func GenerateImpersonationToken(userID uint, email string, role string, sessionID uint, adminID uint) (string, error) {
 	claims := Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		UserID:         userID,
+		Email:          email,
+		Role:           role,
+		SessionID:      sessionID,
+		ImpersonatorID: adminID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			Issuer:    Issuer(),
+			Audience:  jwt.ClaimStrings{AudienceAccess},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ImpersonationTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	return signClaims(claims)
 }
 
 func ValidateToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(os.Getenv("JWT_SECRET")), nil
-	})
+	token, err := parseClaims(tokenString, &Claims{}, jwt.WithIssuer(Issuer()), jwt.WithAudience(AudienceAccess))
 
 	if err != nil {
 		return nil, err
@@ -45,3 +100,68 @@ func ValidateToken(tokenString string) (*Claims, error) {
 
 	return nil, errors.New("invalid token")
 }
+
+// GenerateOpaqueToken returns a random URL-safe token suitable for refresh
+// tokens and other single-use secrets. Only its hash should be stored.
+func GenerateOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken returns the hex-encoded SHA-256 of an opaque token for storage.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Purposes for single-purpose action tokens
+const (
+	PurposeVerifyEmail   = "verify_email"
+	PurposeLogin2FA      = "login_2fa"
+	PurposeEnroll2FA     = "enroll_2fa"
+	PurposeDeleteAccount = "delete_account"
+)
+
+// ActionClaims back short-lived tokens that authorize one specific action,
+// such as confirming an email address. Their audience keeps them from being
+// accepted as access tokens or for another purpose.
+type ActionClaims struct {
+	UserID  uint   `json:"user_id"`
+	Email   string `json:"email"`
+	Purpose string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+func GenerateActionToken(userID uint, email string, purpose string, ttl time.Duration) (string, error) {
+	claims := ActionClaims{
+		UserID:  userID,
+		Email:   email,
+		Purpose: purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    Issuer(),
+			Audience:  jwt.ClaimStrings{actionAudience(purpose)},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Subject:   purpose,
+		},
+	}
+
+	return signClaims(claims)
+}
+
+func ValidateActionToken(tokenString string, purpose string) (*ActionClaims, error) {
+	token, err := parseClaims(tokenString, &ActionClaims{}, jwt.WithIssuer(Issuer()), jwt.WithAudience(actionAudience(purpose)), jwt.WithSubject(purpose))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*ActionClaims); ok && token.Valid && claims.Purpose == purpose {
+		return claims, nil
+	}
+
+	return nil, errors.New("invalid token")
+}
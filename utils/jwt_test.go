@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestMain(m *testing.M) {
+	os.Setenv("JWT_KEY_DIR", "")
+	os.Setenv("JWT_SECRET", "test-secret")
+	os.Exit(m.Run())
+}
+
+func audience(t *testing.T, token string) jwt.ClaimStrings {
+	t.Helper()
+
+	claims := jwt.RegisteredClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, &claims); err != nil {
+		t.Fatal(err)
+	}
+	return claims.Audience
+}
+
+func TestTokenAudiences(t *testing.T) {
+	access, err := GenerateToken(1, "ada@example.com", "creative", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	impersonation, err := GenerateImpersonationToken(1, "ada@example.com", "creative", 8, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify, err := GenerateActionToken(1, "ada@example.com", PurposeVerifyEmail, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, token := range map[string]string{"access": access, "impersonation": impersonation} {
+		if aud := audience(t, token); len(aud) != 1 || aud[0] != AudienceAccess {
+			t.Errorf("%s token: aud %v", name, aud)
+		}
+		if _, err := ValidateToken(token); err != nil {
+			t.Errorf("%s token rejected: %v", name, err)
+		}
+		if _, err := ValidateActionToken(token, PurposeVerifyEmail); err == nil {
+			t.Errorf("%s token accepted as an action token", name)
+		}
+	}
+
+	if aud := audience(t, verify); len(aud) != 1 || aud[0] != "action:"+PurposeVerifyEmail {
+		t.Errorf("verification token: aud %v", aud)
+	}
+	if _, err := ValidateActionToken(verify, PurposeVerifyEmail); err != nil {
+		t.Errorf("verification token rejected: %v", err)
+	}
+	if _, err := ValidateToken(verify); err == nil {
+		t.Error("verification token accepted as an access token")
+	}
+	if _, err := ValidateActionToken(verify, PurposeLogin2FA); err == nil {
+		t.Error("verification token accepted as a 2FA challenge")
+	}
+}
@@ -0,0 +1,239 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// signingKey is one entry of the key ring. Retired keys may only carry a
+// public key; they still verify tokens but are never used to sign.
+type signingKey struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private crypto.Signer
+	Public  crypto.PublicKey
+}
+
+type keyRing struct {
+	active *signingKey
+	keys   map[string]*signingKey
+	// secret is only set in legacy HS256 mode (no JWT_KEY_DIR)
+	secret []byte
+}
+
+var (
+	ring     *keyRing
+	ringErr  error
+	ringOnce sync.Once
+)
+
+// InitKeys loads the signing keys from the environment. It is called once at
+// startup so a broken key directory fails fast instead of on the first login.
+//
+// With JWT_KEY_DIR set, every *.pem file in it is loaded; the file name (minus
+// extension) is the key's kid. RSA keys sign with RS256 and Ed25519 keys with
+// EdDSA. JWT_SIGNING_KID picks the active key, defaulting to the
+// lexicographically last private key. Without JWT_KEY_DIR, tokens fall back
+// to HS256 with JWT_SECRET.
+func InitKeys() error {
+	ringOnce.Do(func() {
+		ring, ringErr = loadKeyRing()
+	})
+	return ringErr
+}
+
+func getKeyRing() (*keyRing, error) {
+	if err := InitKeys(); err != nil {
+		return nil, err
+	}
+	return ring, nil
+}
+
+func loadKeyRing() (*keyRing, error) {
+	dir := os.Getenv("JWT_KEY_DIR")
+	if dir == "" {
+		secret := os.Getenv("JWT_SECRET")
+		if secret == "" {
+			return nil, errors.New("neither JWT_KEY_DIR nor JWT_SECRET is set")
+		}
+		return &keyRing{secret: []byte(secret)}, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	kr := &keyRing{keys: make(map[string]*signingKey)}
+	for _, file := range files {
+		kid := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		key, err := loadKeyFile(kid, file)
+		if err != nil {
+			return nil, fmt.Errorf("loading %s: %w", file, err)
+		}
+		kr.keys[kid] = key
+		if key.Private != nil {
+			kr.active = key
+		}
+	}
+
+	if kid := os.Getenv("JWT_SIGNING_KID"); kid != "" {
+		key, ok := kr.keys[kid]
+		if !ok || key.Private == nil {
+			return nil, fmt.Errorf("JWT_SIGNING_KID %q has no private key in %s", kid, dir)
+		}
+		kr.active = key
+	}
+
+	if kr.active == nil {
+		return nil, fmt.Errorf("no private signing key found in %s; create one with `go run ./cmd/genkey` or unset JWT_KEY_DIR to use JWT_SECRET", dir)
+	}
+	return kr, nil
+}
+
+func loadKeyFile(kid, file string) (*signingKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &signingKey{ID: kid}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method, key.Private, key.Public = jwt.SigningMethodRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.Method, key.Public = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.Method, key.Private, key.Public = jwt.SigningMethodEdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.Method, key.Public = jwt.SigningMethodEdDSA, k
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+	return key, nil
+}
+
+// signClaims signs claims with the active key, setting the kid header
+func signClaims(claims jwt.Claims) (string, error) {
+	kr, err := getKeyRing()
+	if err != nil {
+		return "", err
+	}
+
+	if kr.secret != nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(kr.secret)
+	}
+
+	token := jwt.NewWithClaims(kr.active.Method, claims)
+	token.Header["kid"] = kr.active.ID
+	return token.SignedString(kr.active.Private)
+}
+
+// parseClaims verifies a token against the key ring, resolving keys by kid
+func parseClaims(tokenString string, claims jwt.Claims, opts ...jwt.ParserOption) (*jwt.Token, error) {
+	kr, err := getKeyRing()
+	if err != nil {
+		return nil, err
+	}
+
+	if kr.secret != nil {
+		opts = append(opts, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+		return jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+			return kr.secret, nil
+		}, opts...)
+	}
+
+	opts = append(opts, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}))
+	return jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := kr.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		// Don't let a token pick a different algorithm than its key
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, errors.New("signing method does not match key")
+		}
+		return key.Public, nil
+	}, opts...)
+}
+
+// JWK is a public key in JSON Web Key format (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// PublicJWKS returns every verification key in the ring. In legacy HS256 mode
+// there is nothing that can be published, so the set is empty.
+func PublicJWKS() ([]JWK, error) {
+	kr, err := getKeyRing()
+	if err != nil {
+		return nil, err
+	}
+
+	jwks := []JWK{}
+	ids := make([]string, 0, len(kr.keys))
+	for kid := range kr.keys {
+		ids = append(ids, kid)
+	}
+	sort.Strings(ids)
+
+	for _, kid := range ids {
+		key := kr.keys[kid]
+		jwk := JWK{Kid: kid, Use: "sig", Alg: key.Method.Alg()}
+		switch pub := key.Public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		jwks = append(jwks, jwk)
+	}
+	return jwks, nil
+}
@@ -0,0 +1,307 @@
+package utils
+
+import (
+	"log"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"jobconnect-backend/config"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AttemptRecord is the failed-login state kept for one key
+type AttemptRecord struct {
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   time.Time
+}
+
+// AttemptStore persists failed-login counters keyed by account or IP
+type AttemptStore interface {
+	Get(key string) (AttemptRecord, error)
+	// Increment adds one failure, starting over if the last failure is older
+	// than resetAfter, and returns the updated record
+	Increment(key string, now time.Time, resetAfter time.Duration) (AttemptRecord, error)
+	Lock(key string, until time.Time) error
+	Reset(key string) error
+}
+
+// MemoryAttemptStore keeps counters in process memory. Suitable for tests and
+// single-instance deployments.
+type MemoryAttemptStore struct {
+	mu      sync.Mutex
+	records map[string]AttemptRecord
+}
+
+func NewMemoryAttemptStore() *MemoryAttemptStore {
+	return &MemoryAttemptStore{records: make(map[string]AttemptRecord)}
+}
+
+func (s *MemoryAttemptStore) Get(key string) (AttemptRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.records[key], nil
+}
+
+func (s *MemoryAttemptStore) Increment(key string, now time.Time, resetAfter time.Duration) (AttemptRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec := s.records[key]
+	if now.Sub(rec.LastFailureAt) > resetAfter {
+		rec = AttemptRecord{}
+	}
+	rec.Failures++
+	rec.LastFailureAt = now
+	s.records[key] = rec
+	return rec, nil
+}
+
+func (s *MemoryAttemptStore) Lock(key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec := s.records[key]
+	rec.LockedUntil = until
+	s.records[key] = rec
+	return nil
+}
+
+func (s *MemoryAttemptStore) Reset(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+// PostgresAttemptStore keeps counters in the login_attempts table so limits
+// hold across server instances
+type PostgresAttemptStore struct {
+	DB *gorm.DB
+}
+
+func (s *PostgresAttemptStore) Get(key string) (AttemptRecord, error) {
+	var attempt config.LoginAttempt
+	err := s.DB.Where("key = ?", key).First(&attempt).Error
+	if err == gorm.ErrRecordNotFound {
+		return AttemptRecord{}, nil
+	}
+	if err != nil {
+		return AttemptRecord{}, err
+	}
+	return AttemptRecord{
+		Failures:      attempt.Failures,
+		LastFailureAt: attempt.LastFailureAt,
+		LockedUntil:   attempt.LockedUntil,
+	}, nil
+}
+
+func (s *PostgresAttemptStore) Increment(key string, now time.Time, resetAfter time.Duration) (AttemptRecord, error) {
+	// Single upsert so concurrent failures can't lose increments
+	attempt := config.LoginAttempt{Key: key, Failures: 1, LastFailureAt: now}
+	err := s.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"failures": gorm.Expr(
+				"CASE WHEN login_attempts.last_failure_at < ? THEN 1 ELSE login_attempts.failures + 1 END",
+				now.Add(-resetAfter),
+			),
+			"last_failure_at": now,
+			"updated_at":      now,
+		}),
+	}).Create(&attempt).Error
+	if err != nil {
+		return AttemptRecord{}, err
+	}
+	return s.Get(key)
+}
+
+func (s *PostgresAttemptStore) Lock(key string, until time.Time) error {
+	return s.DB.Model(&config.LoginAttempt{}).Where("key = ?", key).Update("locked_until", until).Error
+}
+
+func (s *PostgresAttemptStore) Reset(key string) error {
+	return s.DB.Where("key = ?", key).Delete(&config.LoginAttempt{}).Error
+}
+
+// LimitPolicy controls backoff and lockout for one kind of key
+type LimitPolicy struct {
+	FreeAttempts    int           // failures allowed before any delay
+	BaseDelay       time.Duration // first delay, doubled per further failure
+	MaxDelay        time.Duration
+	MaxFailures     int // failures that trigger a full lockout
+	LockoutDuration time.Duration
+	ResetAfter      time.Duration // quiet period after which the counter starts over
+}
+
+var (
+	// AccountPolicy protects a single account from password guessing
+	AccountPolicy = LimitPolicy{
+		FreeAttempts:    3,
+		BaseDelay:       time.Second,
+		MaxDelay:        time.Minute,
+		MaxFailures:     10,
+		LockoutDuration: 15 * time.Minute,
+		ResetAfter:      time.Hour,
+	}
+	// IPPolicy is looser since many users may share one address
+	IPPolicy = LimitPolicy{
+		FreeAttempts:    20,
+		BaseDelay:       time.Second,
+		MaxDelay:        time.Minute,
+		MaxFailures:     100,
+		LockoutDuration: time.Hour,
+		ResetAfter:      time.Hour,
+	}
+	// MailPolicy limits how often one address can be sent reset or
+	// verification links, so the endpoints can't be used to flood an inbox
+	MailPolicy = LimitPolicy{
+		FreeAttempts:    3,
+		BaseDelay:       time.Minute,
+		MaxDelay:        15 * time.Minute,
+		MaxFailures:     10,
+		LockoutDuration: time.Hour,
+		ResetAfter:      time.Hour,
+	}
+	// MailIPPolicy limits how many addresses one IP can have mailed
+	MailIPPolicy = LimitPolicy{
+		FreeAttempts:    10,
+		BaseDelay:       10 * time.Second,
+		MaxDelay:        5 * time.Minute,
+		MaxFailures:     50,
+		LockoutDuration: time.Hour,
+		ResetAfter:      time.Hour,
+	}
+)
+
+// LoginLimiter applies exponential backoff and lockout to failed logins, and
+// to requests that email reset or verification links
+type LoginLimiter struct {
+	Store AttemptStore
+}
+
+func AccountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func IPKey(ip string) string {
+	return "ip:" + ip
+}
+
+// RetryAfter returns how long the caller must wait before trying to log in
+// with this email from this IP. Zero means the attempt may proceed.
+func (l *LoginLimiter) RetryAfter(email, ip string) (time.Duration, error) {
+	return l.retryAfter(AccountKey(email), IPKey(ip))
+}
+
+// RecordFailure counts a failed login and locks the keys as the policy requires
+func (l *LoginLimiter) RecordFailure(email, ip string) error {
+	return l.record(map[string]LimitPolicy{AccountKey(email): AccountPolicy, IPKey(ip): IPPolicy})
+}
+
+// MailKey scopes a key to one kind of email ("reset", "verify"), so sending
+// mail never counts against logins or the other kind
+func MailKey(kind, key string) string {
+	return kind + ":" + key
+}
+
+// MailRetryAfter is RetryAfter for requests that email a link to the address
+func (l *LoginLimiter) MailRetryAfter(kind, email, ip string) (time.Duration, error) {
+	return l.retryAfter(MailKey(kind, AccountKey(email)), MailKey(kind, IPKey(ip)))
+}
+
+// RecordMail counts a request to email the address, whether or not it
+// belongs to an account, so the limit doesn't reveal which ones do
+func (l *LoginLimiter) RecordMail(kind, email, ip string) error {
+	return l.record(map[string]LimitPolicy{
+		MailKey(kind, AccountKey(email)): MailPolicy,
+		MailKey(kind, IPKey(ip)):         MailIPPolicy,
+	})
+}
+
+func (l *LoginLimiter) retryAfter(keys ...string) (time.Duration, error) {
+	now := time.Now()
+	var wait time.Duration
+	for _, key := range keys {
+		rec, err := l.Store.Get(key)
+		if err != nil {
+			return 0, err
+		}
+		if d := rec.LockedUntil.Sub(now); d > wait {
+			wait = d
+		}
+	}
+	return wait, nil
+}
+
+func (l *LoginLimiter) record(policies map[string]LimitPolicy) error {
+	now := time.Now()
+	for key, policy := range policies {
+		rec, err := l.Store.Increment(key, now, policy.ResetAfter)
+		if err != nil {
+			return err
+		}
+		if d := policy.delay(rec.Failures); d > 0 {
+			if err := l.Store.Lock(key, now.Add(d)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RecordSuccess clears the account's counter. The IP counter is left alone so
+// one valid login can't be used to reset guessing against other accounts.
+func (l *LoginLimiter) RecordSuccess(email string) error {
+	return l.Store.Reset(AccountKey(email))
+}
+
+// Unlock clears any lockout on an account
+func (l *LoginLimiter) Unlock(email string) error {
+	return l.Store.Reset(AccountKey(email))
+}
+
+func (p LimitPolicy) delay(failures int) time.Duration {
+	if failures >= p.MaxFailures {
+		return p.LockoutDuration
+	}
+	if failures < p.FreeAttempts {
+		return 0
+	}
+	d := time.Duration(float64(p.BaseDelay) * math.Pow(2, float64(failures-p.FreeAttempts)))
+	if d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return d
+}
+
+var (
+	loginLimiter     *LoginLimiter
+	loginLimiterOnce sync.Once
+)
+
+// GetLoginLimiter returns the configured limiter, built on first use from
+// LOGIN_ATTEMPT_STORE ("postgres" by default, or "memory")
+func GetLoginLimiter() *LoginLimiter {
+	loginLimiterOnce.Do(func() {
+		if loginLimiter != nil {
+			return
+		}
+		if os.Getenv("LOGIN_ATTEMPT_STORE") == "memory" {
+			log.Println("Using in-memory login attempt store")
+			loginLimiter = &LoginLimiter{Store: NewMemoryAttemptStore()}
+			return
+		}
+		loginLimiter = &LoginLimiter{Store: &PostgresAttemptStore{DB: config.DB}}
+	})
+	return loginLimiter
+}
+
+// SetLoginLimiter overrides the limiter, e.g. with a memory store in tests
+func SetLoginLimiter(l *LoginLimiter) {
+	loginLimiterOnce.Do(func() {})
+	loginLimiter = l
+}
@@ -0,0 +1,175 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Mailer sends transactional emails (verification, password reset, ...)
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// SMTPMailer delivers plain-text mail through an SMTP relay
+type SMTPMailer struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	// From is the From: header, display name included
+	From string
+
+	// sender is the bare address from From, used as the envelope sender
+	sender string
+}
+
+// NewSMTPMailer builds an SMTPMailer. from may carry a display name, as in
+// "RosyArtGrid <no-reply@rosyartgrid.com>".
+func NewSMTPMailer(host, port, username, password, from string) (*SMTPMailer, error) {
+	addr, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", from, err)
+	}
+	return &SMTPMailer{
+		Host:     host,
+		Port:     port,
+		Username: username,
+		Password: password,
+		From:     addr.String(),
+		sender:   addr.Address,
+	}, nil
+}
+
+func (m *SMTPMailer) Send(to, subject, body string) error {
+	msg, err := m.message(to, subject, body)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+	return smtp.SendMail(m.Host+":"+m.Port, auth, m.sender, []string{to}, msg)
+}
+
+// message builds the raw message. The subject is Q-encoded, so any UTF-8
+// text is safe in it once line breaks are ruled out.
+func (m *SMTPMailer) message(to, subject, body string) ([]byte, error) {
+	if err := checkHeaders(to, subject); err != nil {
+		return nil, err
+	}
+
+	return []byte(strings.Join([]string{
+		"From: " + m.From,
+		"To: " + to,
+		"Subject: " + mime.QEncoding.Encode("utf-8", subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=\"UTF-8\"",
+		"",
+		body,
+	}, "\r\n")), nil
+}
+
+// ErrHeaderInjection is returned for a recipient or subject with a line
+// break, which would let it add headers of its own
+var ErrHeaderInjection = errors.New("mail header contains a line break")
+
+func checkHeaders(values ...string) error {
+	for _, value := range values {
+		if strings.ContainsAny(value, "\r\n") {
+			return ErrHeaderInjection
+		}
+	}
+	return nil
+}
+
+// SentMail is a message captured by MemoryMailer
+type SentMail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// MemoryMailer keeps messages in memory instead of sending them. Used in
+// tests and local development when no SMTP relay is configured.
+type MemoryMailer struct {
+	mu   sync.Mutex
+	Sent []SentMail
+}
+
+func (m *MemoryMailer) Send(to, subject, body string) error {
+	// Same checks as SMTPMailer, so tests catch what SMTP would reject
+	if err := checkHeaders(to, subject); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Sent = append(m.Sent, SentMail{To: to, Subject: subject, Body: body})
+	return nil
+}
+
+// Last returns the most recent message sent to the given address
+func (m *MemoryMailer) Last(to string) (SentMail, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.Sent) - 1; i >= 0; i-- {
+		if m.Sent[i].To == to {
+			return m.Sent[i], true
+		}
+	}
+	return SentMail{}, false
+}
+
+var (
+	mailer     Mailer
+	mailerOnce sync.Once
+)
+
+// GetMailer returns the configured mailer, building it from the environment
+// on first use. Without SMTP_HOST, mail is kept in memory.
+func GetMailer() Mailer {
+	mailerOnce.Do(func() {
+		if mailer != nil {
+			return
+		}
+		host := os.Getenv("SMTP_HOST")
+		if host == "" {
+			log.Println("SMTP_HOST not set, emails will not be delivered")
+			mailer = &MemoryMailer{}
+			return
+		}
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "587"
+		}
+		smtpMailer, err := NewSMTPMailer(host, port, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), os.Getenv("MAIL_FROM"))
+		if err != nil {
+			log.Println("Warning: MAIL_FROM:", err, "- emails will not be delivered")
+			mailer = &MemoryMailer{}
+			return
+		}
+		mailer = smtpMailer
+	})
+	return mailer
+}
+
+// SetMailer overrides the mailer, e.g. with a MemoryMailer in tests
+func SetMailer(m Mailer) {
+	mailerOnce.Do(func() {})
+	mailer = m
+}
+
+// FrontendLink builds an absolute link into the frontend app
+func FrontendLink(path string, token string) string {
+	base := strings.TrimRight(os.Getenv("FRONTEND_URL"), "/")
+	return fmt.Sprintf("%s%s?token=%s", base, path, token)
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMailerRejectsHeaderInjection(t *testing.T) {
+	m, err := NewSMTPMailer("localhost", "25", "", "", "RosyArtGrid <no-reply@rosyartgrid.test>")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, header := range []struct{ to, subject string }{
+		{"ada@example.com", "Hello\r\nBcc: eve@example.com"},
+		{"ada@example.com", "Hello\nBcc: eve@example.com"},
+		{"ada@example.com\r\nBcc: eve@example.com", "Hello"},
+	} {
+		if _, err := m.message(header.to, header.subject, "body"); !errors.Is(err, ErrHeaderInjection) {
+			t.Errorf("to %q, subject %q: got %v, want ErrHeaderInjection", header.to, header.subject, err)
+		}
+		if err := (&MemoryMailer{}).Send(header.to, header.subject, "body"); !errors.Is(err, ErrHeaderInjection) {
+			t.Errorf("memory mailer, to %q, subject %q: got %v", header.to, header.subject, err)
+		}
+	}
+}
+
+func TestMailerEncodesSubject(t *testing.T) {
+	m, err := NewSMTPMailer("localhost", "25", "", "", "no-reply@rosyartgrid.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := m.message("ada@example.com", "Join Café Noir on RosyArtGrid", "body")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(msg), "Subject: =?utf-8?q?Join_Caf=C3=A9_Noir_on_RosyArtGrid?=\r\n") {
+		t.Fatalf("subject not Q-encoded:\n%s", msg)
+	}
+
+	// Plain ASCII subjects go out as they are
+	msg, _ = m.message("ada@example.com", "Verify your email", "body")
+	if !strings.Contains(string(msg), "Subject: Verify your email\r\n") {
+		t.Fatalf("ASCII subject changed:\n%s", msg)
+	}
+}
@@ -0,0 +1,147 @@
+package utils
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"jobconnect-backend/config"
+
+	"gorm.io/gorm/clause"
+)
+
+// Permissions checked by RequirePermission and ownership checks. The *.any
+// permissions let a role act on resources owned by other users.
+const (
+	PermProjectCreate    = "project.create"
+	PermProjectUpdateAny = "project.update.any"
+	PermProjectDeleteAny = "project.delete.any"
+	PermProjectFeature   = "project.feature"
+	PermCommentCreate    = "comment.create"
+	PermCommentModerate  = "comment.moderate"
+	PermUserManage       = "user.manage"
+	PermUserBan          = "user.ban"
+	PermRoleManage       = "role.manage"
+	PermStatsView        = "stats.view"
+	PermAuditView        = "audit.view"
+	PermUserImpersonate  = "user.impersonate"
+	PermCompanyManage    = "company.manage"
+	PermTalentSearch     = "talent.search"
+	PermShortlistManage  = "shortlist.manage"
+	PermInquirySend      = "inquiry.send"
+	PermGigManage        = "gig.manage"
+	PermGigApply         = "gig.apply"
+)
+
+// AllPermissions lists every known permission
+var AllPermissions = []string{
+	PermProjectCreate,
+	PermProjectUpdateAny,
+	PermProjectDeleteAny,
+	PermProjectFeature,
+	PermCommentCreate,
+	PermCommentModerate,
+	PermUserManage,
+	PermUserBan,
+	PermRoleManage,
+	PermStatsView,
+	PermAuditView,
+	PermUserImpersonate,
+	PermCompanyManage,
+	PermTalentSearch,
+	PermShortlistManage,
+	PermInquirySend,
+	PermGigManage,
+	PermGigApply,
+}
+
+// DefaultRolePermissions is seeded into role_permissions (see SeedPermissions).
+// After that the database is the source of truth.
+var DefaultRolePermissions = map[string][]string{
+	"creative": {PermProjectCreate, PermCommentCreate, PermGigApply},
+	"company":  {PermProjectCreate, PermCommentCreate, PermCompanyManage, PermTalentSearch, PermShortlistManage, PermInquirySend, PermGigManage},
+	"admin":    AllPermissions,
+}
+
+// ValidPermission reports whether perm is a known permission
+func ValidPermission(perm string) bool {
+	for _, p := range AllPermissions {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// SeedPermissions installs default grants for every permission the database
+// has never seen, so permissions added in new releases reach existing
+// installs while grants edited by admins are left alone
+func SeedPermissions() {
+	var known []string
+	if err := config.DB.Model(&config.RolePermission{}).Distinct().Pluck("permission", &known).Error; err != nil {
+		log.Println("Warning: Failed to load role permissions:", err)
+		return
+	}
+	seen := make(map[string]bool)
+	for _, perm := range known {
+		seen[perm] = true
+	}
+
+	var rows []config.RolePermission
+	for role, perms := range DefaultRolePermissions {
+		for _, perm := range perms {
+			if !seen[perm] {
+				rows = append(rows, config.RolePermission{Role: role, Permission: perm})
+			}
+		}
+	}
+	if len(rows) == 0 {
+		return
+	}
+	if err := config.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+		log.Println("Warning: Failed to seed role permissions:", err)
+	}
+}
+
+const permissionCacheTTL = 30 * time.Second
+
+var (
+	permissionMu       sync.Mutex
+	permissionCache    map[string]map[string]bool
+	permissionLoadedAt time.Time
+)
+
+// RoleHasPermission checks the role's permissions, cached briefly so the
+// check doesn't cost a query per request
+func RoleHasPermission(role, perm string) bool {
+	permissionMu.Lock()
+	defer permissionMu.Unlock()
+
+	if permissionCache == nil || time.Since(permissionLoadedAt) > permissionCacheTTL {
+		var rows []config.RolePermission
+		if err := config.DB.Find(&rows).Error; err != nil {
+			log.Println("Warning: Failed to load role permissions:", err)
+			if permissionCache == nil {
+				return false
+			}
+		} else {
+			cache := make(map[string]map[string]bool)
+			for _, row := range rows {
+				if cache[row.Role] == nil {
+					cache[row.Role] = make(map[string]bool)
+				}
+				cache[row.Role][row.Permission] = true
+			}
+			permissionCache, permissionLoadedAt = cache, time.Now()
+		}
+	}
+
+	return permissionCache[role][perm]
+}
+
+// InvalidatePermissionCache forces the next check to reload from the database
+func InvalidatePermissionCache() {
+	permissionMu.Lock()
+	defer permissionMu.Unlock()
+	permissionCache = nil
+}
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// Hosts accepted for each social profile link, subdomains included
+var (
+	BehanceHosts  = []string{"behance.net"}
+	DribbbleHosts = []string{"dribbble.com"}
+	LinkedInHosts = []string{"linkedin.com"}
+	TwitterHosts  = []string{"twitter.com", "x.com"}
+)
+
+// ValidateProfileURL checks that raw is an absolute http(s) URL and, when
+// hosts are given, that it points at one of them. An empty string is valid
+// and clears the link. The trimmed URL is returned.
+func ValidateProfileURL(raw string, hosts ...string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+	if len(raw) > 255 {
+		return "", errors.New("must be at most 255 characters")
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", errors.New("must be a valid http or https URL")
+	}
+	if len(hosts) == 0 {
+		return raw, nil
+	}
+
+	host := strings.ToLower(u.Hostname())
+	for _, allowed := range hosts {
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return raw, nil
+		}
+	}
+	return "", errors.New("must be a " + strings.Join(hosts, " or ") + " URL")
+}
+
+// NormalizeSkills trims and collapses whitespace in each skill, drops empty
+// entries and case-insensitive duplicates, and removes commas since skills are
+// stored comma-separated
+func NormalizeSkills(skills []string) []string {
+	normalized := []string{}
+	seen := make(map[string]bool)
+	for _, skill := range skills {
+		skill = strings.Join(strings.Fields(strings.ReplaceAll(skill, ",", " ")), " ")
+		key := strings.ToLower(skill)
+		if skill == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, skill)
+	}
+	return normalized
+}
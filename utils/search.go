@@ -0,0 +1,11 @@
+package utils
+
+import "strings"
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EscapeLike escapes LIKE wildcards in user input, so it matches literally
+// with the default backslash escape character
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
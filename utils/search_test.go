@@ -0,0 +1,16 @@
+package utils
+
+import "testing"
+
+func TestEscapeLike(t *testing.T) {
+	for input, want := range map[string]string{
+		"illustration": "illustration",
+		"100%":         `100\%`,
+		"3d_art":       `3d\_art`,
+		`back\slash`:   `back\\slash`,
+	} {
+		if got := EscapeLike(input); got != want {
+			t.Errorf("EscapeLike(%q) = %q, want %q", input, got, want)
+		}
+	}
+}